@@ -1,5 +1,12 @@
 package art
 
+import (
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
 // NodeType - adaptive radix tree node type.
 type NodeType uint8
 
@@ -12,32 +19,214 @@ const (
 	Node256
 )
 
+// String returns the name of the node type.
+func (t NodeType) String() string {
+	switch t {
+	case LeafNode:
+		return "Leaf"
+	case Node4:
+		return "Node4"
+	case Node16:
+		return "Node16"
+	case Node48:
+		return "Node48"
+	case Node256:
+		return "Node256"
+	}
+	return fmt.Sprintf("NodeType(%d)", uint8(t))
+}
+
 // Key type.
 type Key = []byte
 
 // Value type.
 type Value = interface{}
 
-// Node interfaces
-type Node interface {
+// TypedNode - node interface of a tree storing values of type V.
+type TypedNode[V any] interface {
 	NodeType() NodeType
 	Key() Key
-	Value() Value
+	Value() V
 }
 
+// Node interfaces
+type Node = TypedNode[Value]
+
+// TypedCallback - callback function that is passed in Each of a tree storing values of type V.
+type TypedCallback[V any] func(node TypedNode[V])
+
 // Callback - callback function that is passed in Each.
-type Callback func(node Node)
+type Callback = TypedCallback[Value]
+
+// WalkAction - value returned by the callback of Walk to control the traversal.
+type WalkAction uint8
+
+// Walk actions.
+const (
+	WalkContinue     WalkAction = iota // continue the traversal
+	WalkSkipChildren                   // do not visit the children of the current node
+	WalkStop                           // stop the traversal
+)
+
+// WalkNodes - kinds of node visited by a traversal.
+type WalkNodes uint8
+
+// Kinds of visited node.
+const (
+	WalkAllNodes   WalkNodes = iota // visit leaves and inner nodes
+	WalkLeaves                      // visit leaves only
+	WalkInnerNodes                  // visit inner nodes only
+)
+
+// WalkOptions - options of a traversal, all nodes are visited in ascending pre-order by default.
+// Children of a node are still traversed when the node itself is not visited.
+// WalkSkipChildren has no effect in post-order, as children are visited before their parent.
+type WalkOptions struct {
+	Nodes      WalkNodes
+	PostOrder  bool
+	Descending bool
+}
+
+// TypedWalkFunc - callback function that is passed in Walk of a tree storing values of type V.
+type TypedWalkFunc[V any] func(node TypedNode[V]) WalkAction
+
+// WalkFunc - callback function that is passed in Walk.
+type WalkFunc = TypedWalkFunc[Value]
+
+// TypedUpsertFunc - function that is passed in Upsert of a tree storing values of type V.
+// It receives the current value of the key and whether the key exists,
+// and returns the value to store.
+type TypedUpsertFunc[V any] func(old V, exists bool) V
+
+// UpsertFunc - function that is passed in Upsert.
+type UpsertFunc = TypedUpsertFunc[Value]
+
+// Bound - kind of a range scan bound.
+type Bound uint8
+
+// Kinds of bound.
+const (
+	Inclusive Bound = iota
+	Exclusive
+	Unbounded
+)
+
+// RangeOptions - bounds of a range scan, both inclusive by default.
+type RangeOptions struct {
+	Lo Bound
+	Hi Bound
+}
+
+// DumpFormat - output format of Dump.
+type DumpFormat uint8
+
+// Formats of Dump.
+const (
+	DumpText DumpFormat = iota // indented text outline
+	DumpDOT                    // Graphviz DOT graph
+)
+
+// TypedTree - adaptive radix tree interface storing values of type V.
+type TypedTree[V any] interface {
+	Insert(key Key, value V)
+	Swap(key Key, value V) (old V, replaced bool)
+	InsertIfAbsent(key Key, value V) (existing V, inserted bool)
+	Upsert(key Key, fn TypedUpsertFunc[V])
+	Search(key Key) (value V)
+	Get(key Key) (value V, ok bool)
+	Delete(key Key) (deleted bool)
+	Remove(key Key) (old V, ok bool)
+	Each(cb TypedCallback[V])
+	EachWith(opts WalkOptions, cb TypedCallback[V])
+	Walk(fn TypedWalkFunc[V])
+	WalkWith(opts WalkOptions, fn TypedWalkFunc[V])
+	Size() int
+	Cursor() TypedCursor[V]
+	Range(lo, hi Key, opts RangeOptions, cb TypedCallback[V])
+	ScanPrefix(prefix Key, cb TypedCallback[V])
+	All() iter.Seq2[Key, V]
+	Backward() iter.Seq2[Key, V]
+	Prefix(prefix Key) iter.Seq2[Key, V]
+	Between(lo, hi Key, opts RangeOptions) iter.Seq2[Key, V]
+	CountPrefix(prefix Key) int
+	CountRange(lo, hi Key, opts RangeOptions) int
+	LongestPrefix(key Key) (matchedKey Key, value V, ok bool)
+	Min() (key Key, value V, ok bool)
+	Max() (key Key, value V, ok bool)
+	Floor(key Key) (floorKey Key, value V, ok bool)
+	Ceiling(key Key) (ceilingKey Key, value V, ok bool)
+	Rank(key Key) int
+	Select(i int) (key Key, value V, ok bool)
+	Snapshot() TypedTree[V]
+	Txn() TypedTxn[V]
+	Save(w io.Writer, codec Codec[V]) error
+	Stats() Stats
+	Validate() error
+	Dump(w io.Writer, format DumpFormat) error
+}
+
+// TypedTxn - transaction on a tree storing values of type V. Writes are applied to a private
+// copy of the tree, and published all at once by Commit or discarded by Rollback.
+// Once closed by either, writes to the transaction panic with ErrTxnClosed. Reads still see
+// the committed tree after Commit, and an empty tree after Rollback.
+type TypedTxn[V any] interface {
+	TypedTree[V]
+	Commit() error
+	Rollback()
+}
+
+// TypedCursor - bidirectional iterator over the leaves of a tree in lexicographical order.
+// Positioning methods return whether the cursor stands on a leaf afterwards.
+// A cursor must not be used after the tree has been modified.
+type TypedCursor[V any] interface {
+	First() bool
+	Last() bool
+	Seek(key Key) bool
+	Next() bool
+	Prev() bool
+	Valid() bool
+	Key() Key
+	Value() V
+}
 
 // Tree - adaptive radix tree interface.
-type Tree interface {
-	Insert(key Key, value Value)
-	Search(key Key) (value Value)
+type Tree = TypedTree[Value]
+
+// Cursor - cursor over a Tree.
+type Cursor = TypedCursor[Value]
+
+// Txn - transaction on a Tree.
+type Txn = TypedTxn[Value]
+
+// Errors returned by Commit.
+var (
+	ErrTxnClosed   = errors.New("art: transaction already committed or rolled back")
+	ErrTxnConflict = errors.New("art: tree modified since the transaction started")
+)
+
+// ConcurrentTree - adaptive radix tree interface storing values of type V,
+// safe for concurrent use by multiple goroutines.
+type ConcurrentTree[V any] interface {
+	Insert(key Key, value V)
+	Search(key Key) (value V)
+	Get(key Key) (value V, ok bool)
 	Delete(key Key) (deleted bool)
-	Each(cb Callback)
+	Each(cb TypedCallback[V])
 	Size() int
 }
 
 // New - creates a new instance of adaptive radix tree.
 func New() Tree {
-	return newArt()
+	return newArt[Value]()
+}
+
+// NewTyped - creates a new instance of adaptive radix tree storing values of type V.
+func NewTyped[V any]() TypedTree[V] {
+	return newArt[V]()
+}
+
+// NewConcurrent - creates a new instance of adaptive radix tree storing values of type V,
+// synchronized with optimistic lock coupling.
+func NewConcurrent[V any]() ConcurrentTree[V] {
+	return newConcurrentArt[V]()
 }
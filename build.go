@@ -0,0 +1,153 @@
+package art
+
+import (
+	"bytes"
+	"errors"
+	"iter"
+)
+
+// ErrNotSorted is returned by BuildSorted when keys are not strictly increasing.
+var ErrNotSorted = errors.New("art: keys are not strictly increasing")
+
+// builtNode is a subtree completed by BuildSorted, not attached to its parent yet.
+type builtNode[V any] struct {
+	node  *artNode[V]
+	depth int // depth of the key bytes the children of an inner node are indexed by
+	key   Key // any key of the subtree
+}
+
+// openNode is an inner node on the rightmost path of the tree built by BuildSorted,
+// which may still get children.
+type openNode[V any] struct {
+	depth    int
+	terminal *artNode[V]
+	keys     []byte
+	children []*artNode[V]
+}
+
+// BuildSorted - creates a new instance of adaptive radix tree from the pairs yielded by seq,
+// whose keys must be in strictly increasing order, such as the All iterator of another tree.
+// The tree is built bottom-up in a single pass, and every inner node is created at its final type
+// once all its children are known.
+func BuildSorted[V any](seq iter.Seq2[Key, V]) (TypedTree[V], error) {
+	t := newArt[V]()
+
+	var stack []*openNode[V]
+	var free []*openNode[V]
+	var last builtNode[V]
+	var prev Key
+	for key, value := range seq {
+		if t.size > 0 && bytes.Compare(prev, key) >= 0 {
+			return nil, ErrNotSorted
+		}
+		leaf := newLeafNode(key, value)
+
+		if last.node != nil {
+			// The keys are sorted, so the new key shares with the previous one
+			// at least as many bytes as with any other key before it.
+			lcp := 0
+			for lcp < len(prev) && lcp < len(key) && prev[lcp] == key[lcp] {
+				lcp++
+			}
+
+			// Complete the nodes of the rightmost path below the new branch.
+			for len(stack) > 0 && stack[len(stack)-1].depth > lcp {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				top.attach(last)
+				last = top.close(last.key)
+				free = append(free, top)
+			}
+			if len(stack) == 0 || stack[len(stack)-1].depth < lcp {
+				var open *openNode[V]
+				if len(free) > 0 {
+					open, free = free[len(free)-1], free[:len(free)-1]
+				} else {
+					open = &openNode[V]{}
+				}
+				open.depth = lcp
+				stack = append(stack, open)
+			}
+			stack[len(stack)-1].attach(last)
+		}
+
+		last = builtNode[V]{node: leaf, key: leaf.leafNode().key}
+		prev = last.key
+		t.size++
+	}
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		top.attach(last)
+		last = top.close(last.key)
+	}
+	if last.node != nil && !last.node.isLeaf() {
+		last.node.setPrefix(last.key, 0, last.depth)
+	}
+	t.root = last.node
+	return t, nil
+}
+
+// attach adds the passed in subtree as the last child of the open node.
+// A leaf whose key ends at the depth of the open node can only be attached first,
+// and becomes its terminal leaf.
+func (o *openNode[V]) attach(child builtNode[V]) {
+	if o.depth == len(child.key) {
+		o.terminal = child.node
+		return
+	}
+	if !child.node.isLeaf() {
+		child.node.setPrefix(child.key, o.depth+1, child.depth)
+	}
+	o.keys = append(o.keys, child.key[o.depth])
+	o.children = append(o.children, child.node)
+}
+
+// close creates the inner node of the smallest type that holds the children of the open node,
+// and resets the open node for reuse.
+func (o *openNode[V]) close(key Key) builtNode[V] {
+	var n *artNode[V]
+	switch size := len(o.children); {
+	case size <= node4Max:
+		n = newNode4[V]()
+		n4 := n.node4()
+		copy(n4.keys[:], o.keys)
+		copy(n4.children[:], o.children)
+	case size <= node16Max:
+		n = newNode16[V]()
+		n16 := n.node16()
+		copy(n16.keys[:], o.keys)
+		copy(n16.children[:], o.children)
+	case size <= node48Max:
+		n = newNode48[V]()
+		n48 := n.node48()
+		for i, keyChar := range o.keys {
+			n48.keys[keyChar] = byte(i + 1)
+			n48.children[i+1] = o.children[i]
+		}
+	default:
+		n = newNode256[V]()
+		n256 := n.node256()
+		for i, keyChar := range o.keys {
+			n256.children[keyChar] = o.children[i]
+		}
+	}
+	n.node().size = len(o.children)
+	n.node().terminal = o.terminal
+	n.countLeaves()
+
+	built := builtNode[V]{node: n, depth: o.depth, key: key}
+	o.terminal = nil
+	o.keys = o.keys[:0]
+	o.children = o.children[:0]
+	return built
+}
+
+// setPrefix sets the compressed path of the inner node to the bytes of the passed in key
+// between from and to.
+func (n *artNode[V]) setPrefix(key Key, from, to int) {
+	node := n.node()
+	node.prefixLen = to - from
+	memcpy(node.prefix[:], key[from:], min(node.prefixLen, maxPrefixLen))
+}
@@ -0,0 +1,166 @@
+package art
+
+import (
+	"bytes"
+	"iter"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"art/testdata"
+)
+
+// sliceIterator returns an iterator passed in BuildSorted that yields the passed in keys,
+// using the index of each key as its value.
+func sliceIterator(keys [][]byte) iter.Seq2[Key, int] {
+	return func(yield func(Key, int) bool) {
+		for i, key := range keys {
+			if !yield(key, i) {
+				return
+			}
+		}
+	}
+}
+
+func TestBuildSortedManyWords(t *testing.T) {
+	words := testdata.LoadTestFile("testdata/data/words.txt")
+	sort.Slice(words, func(i, j int) bool {
+		return bytes.Compare(words[i], words[j]) < 0
+	})
+
+	tree, err := BuildSorted(sliceIterator(words))
+	assert.NoError(t, err)
+	assert.Equal(t, len(words), tree.Size())
+	assert.NoError(t, tree.Validate())
+
+	for i, w := range words {
+		value, ok := tree.Get(w)
+		if !assert.True(t, ok, string(w)) {
+			return
+		}
+		assert.Equal(t, i, value)
+	}
+
+	inserted := newArt[int]()
+	for i, w := range words {
+		inserted.Insert(w, i)
+	}
+	assert.Equal(t, nodeTypeCounts[int](inserted), nodeTypeCounts(tree))
+
+	// The built tree remains fully writable.
+	for _, w := range words {
+		assert.True(t, tree.Delete(w))
+	}
+	assert.Zero(t, tree.Size())
+}
+
+func TestBuildSortedNodeTypes(t *testing.T) {
+	var testData = []struct {
+		totalNodes int
+		expected   NodeType
+	}{
+		{4, Node4},
+		{5, Node16},
+		{17, Node48},
+		{49, Node256},
+		{256, Node256},
+	}
+
+	for _, data := range testData {
+		var keys [][]byte
+		for i := 0; i < data.totalNodes; i++ {
+			keys = append(keys, Key{'p', byte(i)})
+		}
+		built, err := BuildSorted(sliceIterator(keys))
+		assert.NoError(t, err)
+
+		root := built.(*tree[int]).root
+		assert.Equal(t, data.expected, root.nodeType)
+		assert.Equal(t, 1, root.node().prefixLen)
+		for i, k := range keys {
+			assert.Equal(t, i, built.Search(k))
+		}
+	}
+}
+
+func TestBuildSortedRejectsUnsortedKeys(t *testing.T) {
+	_, err := BuildSorted(sliceIterator([][]byte{Key("b"), Key("a")}))
+	assert.Equal(t, ErrNotSorted, err)
+
+	_, err = BuildSorted(sliceIterator([][]byte{Key("a"), Key("a")}))
+	assert.Equal(t, ErrNotSorted, err)
+
+	tree, err := BuildSorted(sliceIterator(nil))
+	assert.NoError(t, err)
+	assert.Zero(t, tree.Size())
+}
+
+func BenchmarkWordsBuildSorted(b *testing.B) {
+	words := testdata.LoadTestFile("testdata/data/words.txt")
+	sort.Slice(words, func(i, j int) bool {
+		return bytes.Compare(words[i], words[j]) < 0
+	})
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		BuildSorted(sliceIterator(words))
+	}
+}
+
+func TestBuildSortedKeysWithPrefixes(t *testing.T) {
+	keys := [][]byte{
+		Key("a"),
+		Key("ab"),
+		Key("ab\x00"),
+		Key("ab\x00\x00"),
+		Key("abc"),
+		Key("b"),
+	}
+	built, err := BuildSorted(sliceIterator(keys))
+	assert.NoError(t, err)
+	assert.NoError(t, built.Validate())
+
+	inserted := newArt[int]()
+	for i, k := range keys {
+		inserted.Insert(k, i)
+		assert.Equal(t, i, built.Search(k))
+	}
+	assert.Equal(t, nodeTypeCounts[int](inserted), nodeTypeCounts(built))
+}
+
+func TestBuildSortedEmptyKey(t *testing.T) {
+	built, err := BuildSorted(sliceIterator([][]byte{Key(""), Key("a"), Key("b")}))
+	assert.NoError(t, err)
+	assert.NoError(t, built.Validate())
+	assert.Equal(t, 0, built.Search(Key("")))
+	assert.Equal(t, 2, built.Search(Key("b")))
+
+	var buf bytes.Buffer
+	assert.NoError(t, built.Save(&buf, GobCodec[int]{}))
+	loaded, err := Load[int](&buf, GobCodec[int]{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, loaded.Size())
+	value, ok := loaded.Get(Key(""))
+	assert.True(t, ok)
+	assert.Equal(t, 0, value)
+}
+
+func TestBuildSortedFromTree(t *testing.T) {
+	words := testdata.LoadTestFile("testdata/data/words.txt")[:10000]
+	source := newArt[int]()
+	for i, w := range words {
+		source.Insert(w, i)
+	}
+
+	built, err := BuildSorted(source.All())
+	assert.NoError(t, err)
+	assert.NoError(t, built.Validate())
+	assert.Equal(t, source.Size(), built.Size())
+	assert.Equal(t, nodeTypeCounts[int](source), nodeTypeCounts(built))
+	for i, w := range words {
+		assert.Equal(t, i, built.Search(w))
+	}
+
+	_, err = BuildSorted(source.Backward())
+	assert.Equal(t, ErrNotSorted, err)
+}
@@ -0,0 +1,711 @@
+package art
+
+import (
+	"bytes"
+	"runtime"
+	"sort"
+	"sync/atomic"
+)
+
+// olcLock is an optimistic version lock as described in
+// "The ART of Practical Synchronization" (Leis et al.).
+// Bit 1 of the version is set while the lock is held, and bit 0 once the node is obsolete.
+type olcLock struct {
+	version atomic.Uint64
+}
+
+// readLock returns the current version, or false if the node is locked or obsolete.
+func (l *olcLock) readLock() (uint64, bool) {
+	v := l.version.Load()
+	if v&3 != 0 {
+		return 0, false
+	}
+	return v, true
+}
+
+// check returns whether the version has not changed since readLock.
+func (l *olcLock) check(v uint64) bool {
+	return l.version.Load() == v
+}
+
+// upgrade acquires the lock if the version has not changed since readLock.
+func (l *olcLock) upgrade(v uint64) bool {
+	return l.version.CompareAndSwap(v, v+2)
+}
+
+// unlock releases the lock and bumps the version.
+func (l *olcLock) unlock() {
+	l.version.Add(2)
+}
+
+// unlockObsolete releases the lock and marks the node as obsolete.
+func (l *olcLock) unlockObsolete() {
+	l.version.Add(3)
+}
+
+// olcNode is a node of the concurrent tree.
+// The type and the prefix are immutable once the node is published. Keys, children and
+// the terminal slot are changed in place under the node lock, and an inner node is only
+// copied when it grows, shrinks or has its prefix split, which also locks its parent.
+type olcNode[V any] struct {
+	lock     olcLock
+	nodeType NodeType
+	prefix   []byte                       // full compressed path of an inner node
+	keys     []atomic.Uint64              // packed key bytes, sorted for Node4 and Node16, child index + 1 by key byte for Node48
+	children []atomic.Pointer[olcNode[V]] // children slots, by key byte for Node256
+	count    atomic.Int32                 // number of children
+	terminal atomic.Pointer[olcNode[V]]   // leaf whose key ends right after the prefix
+	key      Key
+	value    V
+}
+
+// olcEntry is a child of an inner olcNode together with its key byte.
+type olcEntry[V any] struct {
+	key   byte
+	child *olcNode[V]
+}
+
+// olcRef is a slot holding an olcNode, together with the lock protecting it
+// and the version of that lock observed when the slot was read.
+type olcRef[V any] struct {
+	lock    *olcLock
+	version uint64
+	slot    *atomic.Pointer[olcNode[V]]
+}
+
+// concurrentTree - adaptive radix tree type synchronized with optimistic lock coupling.
+type concurrentTree[V any] struct {
+	rootLock olcLock
+	root     atomic.Pointer[olcNode[V]]
+	size     atomic.Int64
+}
+
+// newConcurrentArt returns a concurrent art with 0 nodes.
+func newConcurrentArt[V any]() *concurrentTree[V] {
+	return &concurrentTree[V]{}
+}
+
+// newOLCLeaf creates a leaf olcNode.
+func newOLCLeaf[V any](key []byte, value V) *olcNode[V] {
+	newKey := make([]byte, len(key))
+	copy(newKey, key)
+	return &olcNode[V]{nodeType: LeafNode, key: newKey, value: value}
+}
+
+// newOLCInner creates an inner olcNode of the smallest type that can hold the passed in entries,
+// which must be sorted by key byte.
+func newOLCInner[V any](prefix []byte, terminal *olcNode[V], entries []olcEntry[V]) *olcNode[V] {
+	n := &olcNode[V]{prefix: prefix}
+	n.terminal.Store(terminal)
+
+	switch {
+	case len(entries) <= node4Max:
+		n.nodeType = Node4
+	case len(entries) <= node16Max:
+		n.nodeType = Node16
+	case len(entries) <= node48Max:
+		n.nodeType = Node48
+	default:
+		n.nodeType = Node256
+	}
+
+	switch n.nodeType {
+	case Node4, Node16:
+		size := node4Max
+		if n.nodeType == Node16 {
+			size = node16Max
+		}
+		n.keys = make([]atomic.Uint64, (size+7)/8)
+		n.children = make([]atomic.Pointer[olcNode[V]], size)
+		for i, e := range entries {
+			n.setKeyAt(i, e.key)
+			n.children[i].Store(e.child)
+		}
+	case Node48:
+		n.keys = make([]atomic.Uint64, node256Max/8)
+		n.children = make([]atomic.Pointer[olcNode[V]], node48Max)
+		for i, e := range entries {
+			n.setKeyAt(int(e.key), byte(i+1))
+			n.children[i].Store(e.child)
+		}
+	case Node256:
+		n.children = make([]atomic.Pointer[olcNode[V]], node256Max)
+		for _, e := range entries {
+			n.children[e.key].Store(e.child)
+		}
+	}
+	n.count.Store(int32(len(entries)))
+
+	return n
+}
+
+// keyAt returns the key byte at the passed in index of the packed keys.
+func (n *olcNode[V]) keyAt(i int) byte {
+	return byte(n.keys[i/8].Load() >> (i % 8 * 8))
+}
+
+// setKeyAt sets the key byte at the passed in index of the packed keys.
+// The node lock must be held once the node is published.
+func (n *olcNode[V]) setKeyAt(i int, key byte) {
+	word := &n.keys[i/8]
+	shift := i % 8 * 8
+	word.Store(word.Load()&^(0xff<<shift) | uint64(key)<<shift)
+}
+
+// Key returns the key of the given node, or nil if it is not a leaf.
+func (n *olcNode[V]) Key() Key {
+	return n.key
+}
+
+// Value returns the value of the given node, or the zero value if it is not a leaf.
+func (n *olcNode[V]) Value() V {
+	return n.value
+}
+
+// NodeType returns the nodeType of the given node
+func (n *olcNode[V]) NodeType() NodeType {
+	return n.nodeType
+}
+
+// isLeaf returns whether this particular olcNode is a leaf or not.
+func (n *olcNode[V]) isLeaf() bool { return n.nodeType == LeafNode }
+
+// findSlot returns the children slot of the passed in key byte, or nil if there is none.
+func (n *olcNode[V]) findSlot(key byte) *atomic.Pointer[olcNode[V]] {
+	switch n.nodeType {
+	case Node4, Node16:
+		size := n.numChildren()
+		for i := 0; i < size; i += 8 {
+			word := n.keys[i/8].Load()
+			for j := i; j < size && j < i+8; j++ {
+				if byte(word) == key {
+					return &n.children[j]
+				}
+				word >>= 8
+			}
+		}
+	case Node48:
+		if idx := n.keyAt(int(key)); idx > 0 {
+			return &n.children[idx-1]
+		}
+	case Node256:
+		return &n.children[key]
+	}
+	return nil
+}
+
+// numChildren returns the number of children of the inner olcNode.
+func (n *olcNode[V]) numChildren() int {
+	return int(n.count.Load())
+}
+
+// isFull returns whether the inner olcNode has to grow to hold another child.
+func (n *olcNode[V]) isFull() bool {
+	return n.numChildren() == len(n.children)
+}
+
+// isUnderfull returns whether the inner olcNode has to shrink when holding the passed in
+// number of children. The terminal leaf counts as a child of a Node4.
+func (n *olcNode[V]) isUnderfull(size int, terminal bool) bool {
+	switch n.nodeType {
+	case Node4:
+		if terminal {
+			size++
+		}
+		return size < node4Min
+	case Node16:
+		return size < node16Min
+	case Node48:
+		return size < node48Min
+	default:
+		return size < node256Min
+	}
+}
+
+// entries returns the children of the inner olcNode sorted by key byte.
+func (n *olcNode[V]) entries() []olcEntry[V] {
+	entries := make([]olcEntry[V], 0, n.numChildren()+1)
+	switch n.nodeType {
+	case Node4, Node16:
+		for i := 0; i < n.numChildren(); i++ {
+			entries = append(entries, olcEntry[V]{key: n.keyAt(i), child: n.children[i].Load()})
+		}
+	case Node48:
+		for b := 0; b < node256Max; b++ {
+			if idx := n.keyAt(b); idx > 0 {
+				entries = append(entries, olcEntry[V]{key: byte(b), child: n.children[idx-1].Load()})
+			}
+		}
+	case Node256:
+		for b := range n.children {
+			if child := n.children[b].Load(); child != nil {
+				entries = append(entries, olcEntry[V]{key: byte(b), child: child})
+			}
+		}
+	}
+	return entries
+}
+
+// stableEntries returns the children of the inner olcNode as seen by a single version of its lock.
+func (n *olcNode[V]) stableEntries() []olcEntry[V] {
+	for {
+		// Obsolete nodes are not changed anymore, so only a held lock forces a retry.
+		if v := n.lock.version.Load(); v&2 == 0 {
+			entries := n.entries()
+			if n.lock.check(v) {
+				return entries
+			}
+		}
+		runtime.Gosched()
+	}
+}
+
+// insertChild adds the passed in child to the inner olcNode, which must not be full.
+// The node lock must be held once the node is published.
+func (n *olcNode[V]) insertChild(key byte, child *olcNode[V]) {
+	switch n.nodeType {
+	case Node4, Node16:
+		idx := n.numChildren()
+		for ; idx > 0 && n.keyAt(idx-1) > key; idx-- {
+			n.setKeyAt(idx, n.keyAt(idx-1))
+			n.children[idx].Store(n.children[idx-1].Load())
+		}
+		n.setKeyAt(idx, key)
+		n.children[idx].Store(child)
+	case Node48:
+		idx := 0
+		for n.children[idx].Load() != nil {
+			idx++
+		}
+		n.children[idx].Store(child)
+		n.setKeyAt(int(key), byte(idx+1))
+	case Node256:
+		n.children[key].Store(child)
+	}
+	n.count.Add(1)
+}
+
+// deleteChild removes the child of the passed in key byte from the inner olcNode.
+// The node lock must be held.
+func (n *olcNode[V]) deleteChild(key byte) {
+	switch n.nodeType {
+	case Node4, Node16:
+		size := n.numChildren()
+		idx := 0
+		for n.keyAt(idx) != key {
+			idx++
+		}
+		for ; idx < size-1; idx++ {
+			n.setKeyAt(idx, n.keyAt(idx+1))
+			n.children[idx].Store(n.children[idx+1].Load())
+		}
+		n.children[size-1].Store(nil)
+	case Node48:
+		idx := n.keyAt(int(key))
+		n.setKeyAt(int(key), 0)
+		n.children[idx-1].Store(nil)
+	case Node256:
+		n.children[key].Store(nil)
+	}
+	n.count.Add(-1)
+}
+
+// withChild returns a copy of the inner olcNode with the passed in child added.
+func (n *olcNode[V]) withChild(key byte, child *olcNode[V]) *olcNode[V] {
+	entries := n.entries()
+	idx := sort.Search(len(entries), func(i int) bool {
+		return entries[i].key >= key
+	})
+	entries = append(entries, olcEntry[V]{})
+	copy(entries[idx+1:], entries[idx:])
+	entries[idx] = olcEntry[V]{key: key, child: child}
+	return newOLCInner(n.prefix, n.terminal.Load(), entries)
+}
+
+// withPrefix returns a copy of the inner olcNode with the passed in prefix.
+func (n *olcNode[V]) withPrefix(prefix []byte) *olcNode[V] {
+	return newOLCInner(prefix, n.terminal.Load(), n.entries())
+}
+
+// Insert inserts the passed in value that is indexed by the passed in key into the tree.
+func (t *concurrentTree[V]) Insert(key Key, value V) {
+	for !t.insertHelper(key, value) {
+		runtime.Gosched()
+	}
+}
+
+// insertHelper is a helper function for Insert.
+// It returns false if the operation has to be restarted.
+func (t *concurrentTree[V]) insertHelper(key []byte, value V) bool {
+	parent := olcRef[V]{lock: &t.rootLock, slot: &t.root}
+	var ok bool
+	if parent.version, ok = parent.lock.readLock(); !ok {
+		return false
+	}
+
+	depth := 0
+	for {
+		current := parent.slot.Load()
+		if !parent.lock.check(parent.version) {
+			return false
+		}
+
+		if current == nil {
+			return t.replace(parent, newOLCLeaf(key, value), 1)
+		}
+
+		if current.isLeaf() {
+			if bytes.Equal(current.key, key) {
+				return t.replace(parent, newOLCLeaf(key, value), 0)
+			}
+			limit := depth
+			for limit < len(key) && limit < len(current.key) && key[limit] == current.key[limit] {
+				limit++
+			}
+			prefix := make([]byte, limit-depth)
+			copy(prefix, key[depth:limit])
+			newNode := newOLCInner[V](prefix, nil, nil)
+			newNode = withEntry(newNode, current.key, limit, current)
+			newNode = withEntry(newNode, key, limit, newOLCLeaf(key, value))
+			return t.replace(parent, newNode, 1)
+		}
+
+		version, ok := current.lock.readLock()
+		if !ok {
+			return false
+		}
+
+		mismatch := 0
+		for mismatch < len(current.prefix) && depth+mismatch < len(key) && current.prefix[mismatch] == key[depth+mismatch] {
+			mismatch++
+		}
+		if mismatch < len(current.prefix) {
+			if !parent.lock.upgrade(parent.version) {
+				return false
+			}
+			if !current.lock.upgrade(version) {
+				parent.lock.unlock()
+				return false
+			}
+			child := current.withPrefix(current.prefix[mismatch+1:])
+			newNode := newOLCInner(current.prefix[:mismatch:mismatch], nil, []olcEntry[V]{{key: current.prefix[mismatch], child: child}})
+			newNode = withEntry(newNode, key, depth+mismatch, newOLCLeaf(key, value))
+			parent.slot.Store(newNode)
+			current.lock.unlockObsolete()
+			parent.lock.unlock()
+			t.size.Add(1)
+			return true
+		}
+		depth += len(current.prefix)
+
+		var slot *atomic.Pointer[olcNode[V]]
+		if depth == len(key) {
+			slot = &current.terminal
+		} else {
+			slot = current.findSlot(key[depth])
+		}
+
+		if slot == nil && current.isFull() {
+			// Grow into a copy, which replaces the current node in the parent.
+			if !parent.lock.upgrade(parent.version) {
+				return false
+			}
+			if !current.lock.upgrade(version) {
+				parent.lock.unlock()
+				return false
+			}
+			parent.slot.Store(current.withChild(key[depth], newOLCLeaf(key, value)))
+			current.lock.unlockObsolete()
+			parent.lock.unlock()
+			t.size.Add(1)
+			return true
+		}
+
+		if slot == nil || slot.Load() == nil {
+			if !current.lock.upgrade(version) {
+				return false
+			}
+			if slot == &current.terminal {
+				slot.Store(newOLCLeaf(key, value))
+			} else {
+				current.insertChild(key[depth], newOLCLeaf(key, value))
+			}
+			current.lock.unlock()
+			t.size.Add(1)
+			return true
+		}
+
+		parent = olcRef[V]{lock: &current.lock, version: version, slot: slot}
+		depth++
+	}
+}
+
+// withEntry returns the passed in inner olcNode with the passed in leaf added,
+// either as a child or as the terminal leaf if its key ends at depth.
+// The olcNode must not be published yet.
+func withEntry[V any](n *olcNode[V], key []byte, depth int, leaf *olcNode[V]) *olcNode[V] {
+	if depth == len(key) {
+		n.terminal.Store(leaf)
+		return n
+	}
+	n.insertChild(key[depth], leaf)
+	return n
+}
+
+// replace stores the passed in olcNode in the referenced slot, and adjusts the size by delta.
+// It returns false if the slot has been changed since it was read.
+func (t *concurrentTree[V]) replace(ref olcRef[V], n *olcNode[V], delta int64) bool {
+	if !ref.lock.upgrade(ref.version) {
+		return false
+	}
+	ref.slot.Store(n)
+	ref.lock.unlock()
+	t.size.Add(delta)
+	return true
+}
+
+// Search returns the value of the passed in key, or the zero value if not found.
+func (t *concurrentTree[V]) Search(key Key) V {
+	value, _ := t.Get(key)
+	return value
+}
+
+// Get returns the value of the passed in key, and whether the key is present in the tree.
+func (t *concurrentTree[V]) Get(key Key) (V, bool) {
+	for {
+		leaf, ok := t.searchHelper(key)
+		if !ok {
+			runtime.Gosched()
+			continue
+		}
+		if leaf == nil {
+			var zero V
+			return zero, false
+		}
+		return leaf.value, true
+	}
+}
+
+// searchHelper returns the leaf that matches the passed in key, or nil if not found.
+// It returns false if the operation has to be restarted.
+func (t *concurrentTree[V]) searchHelper(key []byte) (*olcNode[V], bool) {
+	version, ok := t.rootLock.readLock()
+	if !ok {
+		return nil, false
+	}
+	current := t.root.Load()
+	if !t.rootLock.check(version) {
+		return nil, false
+	}
+
+	depth := 0
+	for current != nil {
+		if current.isLeaf() {
+			if bytes.Equal(current.key, key) {
+				return current, true
+			}
+			return nil, true
+		}
+
+		version, ok := current.lock.readLock()
+		if !ok {
+			return nil, false
+		}
+		if !bytes.HasPrefix(key[depth:], current.prefix) {
+			return nil, current.lock.check(version)
+		}
+		depth += len(current.prefix)
+
+		var next *olcNode[V]
+		if depth == len(key) {
+			next = current.terminal.Load()
+		} else if slot := current.findSlot(key[depth]); slot != nil {
+			next = slot.Load()
+		}
+		if !current.lock.check(version) {
+			return nil, false
+		}
+		current = next
+		depth++
+	}
+
+	return nil, true
+}
+
+// Delete deletes the child of the passed in key.
+func (t *concurrentTree[V]) Delete(key Key) bool {
+	for {
+		deleted, ok := t.deleteHelper(key)
+		if ok {
+			return deleted
+		}
+		runtime.Gosched()
+	}
+}
+
+// deleteHelper is a helper function for Delete.
+// It returns false as second result if the operation has to be restarted.
+func (t *concurrentTree[V]) deleteHelper(key []byte) (bool, bool) {
+	parent := olcRef[V]{lock: &t.rootLock, slot: &t.root}
+	var ok bool
+	if parent.version, ok = parent.lock.readLock(); !ok {
+		return false, false
+	}
+	current := parent.slot.Load()
+	if !parent.lock.check(parent.version) {
+		return false, false
+	}
+	if current == nil {
+		return false, true
+	}
+	if current.isLeaf() {
+		if !bytes.Equal(current.key, key) {
+			return false, true
+		}
+		return true, t.replace(parent, nil, -1)
+	}
+
+	depth := 0
+	for {
+		version, ok := current.lock.readLock()
+		if !ok {
+			return false, false
+		}
+		if !bytes.HasPrefix(key[depth:], current.prefix) {
+			return false, current.lock.check(version)
+		}
+		depth += len(current.prefix)
+
+		var slot *atomic.Pointer[olcNode[V]]
+		if depth == len(key) {
+			slot = &current.terminal
+		} else {
+			slot = current.findSlot(key[depth])
+		}
+		var child *olcNode[V]
+		if slot != nil {
+			child = slot.Load()
+		}
+		if !current.lock.check(version) {
+			return false, false
+		}
+		if child == nil {
+			return false, true
+		}
+
+		if child.isLeaf() {
+			if !bytes.Equal(child.key, key) {
+				return false, true
+			}
+			return true, t.removeChild(parent, current, version, key, depth)
+		}
+
+		parent = olcRef[V]{lock: &current.lock, version: version, slot: slot}
+		current = child
+		depth++
+	}
+}
+
+// removeChild removes the leaf of the passed in key from the inner olcNode,
+// where depth is the position of its key byte. The olcNode is replaced in the referenced
+// slot if it shrinks, or collapsed into its last remaining child.
+// It returns false if the operation has to be restarted.
+func (t *concurrentTree[V]) removeChild(parent olcRef[V], current *olcNode[V], version uint64, key []byte, depth int) bool {
+	terminal := current.terminal.Load()
+	numChildren := current.numChildren()
+	if depth == len(key) {
+		terminal = nil
+	} else {
+		numChildren--
+	}
+
+	// Remove in place if the node does not need to shrink.
+	if !current.isUnderfull(numChildren, terminal != nil) {
+		if !current.lock.upgrade(version) {
+			return false
+		}
+		if depth == len(key) {
+			current.terminal.Store(nil)
+		} else {
+			current.deleteChild(key[depth])
+		}
+		current.lock.unlock()
+		t.size.Add(-1)
+		return true
+	}
+
+	if !parent.lock.upgrade(parent.version) {
+		return false
+	}
+	if !current.lock.upgrade(version) {
+		parent.lock.unlock()
+		return false
+	}
+
+	entries := current.entries()
+	if depth < len(key) {
+		idx := sort.Search(len(entries), func(i int) bool {
+			return entries[i].key >= key[depth]
+		})
+		entries = append(entries[:idx], entries[idx+1:]...)
+	}
+
+	var newNode *olcNode[V]
+	switch {
+	case len(entries) == 0:
+		newNode = terminal
+	case len(entries) == 1 && terminal == nil:
+		child := entries[0].child
+		if child.isLeaf() {
+			newNode = child
+			break
+		}
+		childVersion, ok := child.lock.readLock()
+		if !ok || !child.lock.upgrade(childVersion) {
+			current.lock.unlock()
+			parent.lock.unlock()
+			return false
+		}
+		prefix := make([]byte, 0, len(current.prefix)+1+len(child.prefix))
+		prefix = append(append(append(prefix, current.prefix...), entries[0].key), child.prefix...)
+		newNode = child.withPrefix(prefix)
+		child.lock.unlockObsolete()
+	default:
+		newNode = newOLCInner(current.prefix, terminal, entries)
+	}
+
+	parent.slot.Store(newNode)
+	current.lock.unlockObsolete()
+	parent.lock.unlock()
+	t.size.Add(-1)
+	return true
+}
+
+// Each iterate the whole tree with the lexicographical order,
+// and will call the given callback for each tree node.
+// Concurrent writes may or may not be observed by the iteration.
+func (t *concurrentTree[V]) Each(callback TypedCallback[V]) {
+	t.eachHelper(t.root.Load(), callback)
+}
+
+// eachHelper is a helper function of Each.
+func (t *concurrentTree[V]) eachHelper(current *olcNode[V], callback TypedCallback[V]) {
+	if current == nil {
+		return
+	}
+
+	callback(current)
+
+	if current.isLeaf() {
+		return
+	}
+	t.eachHelper(current.terminal.Load(), callback)
+	for _, e := range current.stableEntries() {
+		t.eachHelper(e.child, callback)
+	}
+}
+
+// Size returns the number of leaves (key-value) in the tree.
+func (t *concurrentTree[V]) Size() int {
+	return int(t.size.Load())
+}
@@ -0,0 +1,256 @@
+package art
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"art/testdata"
+)
+
+func TestConcurrentTreeInsertSearchDelete(t *testing.T) {
+	tree := newConcurrentArt[Value]()
+
+	keys := []string{"a", "ab", "abc", "abd", "b", "averyveryverylongprefix/1", "averyveryverylongprefix/2"}
+	for _, k := range keys {
+		tree.Insert(Key(k), k)
+	}
+	tree.Insert(Key("ab"), "AB")
+
+	assert.Equal(t, len(keys), tree.Size())
+	assert.Equal(t, "AB", tree.Search(Key("ab")))
+	assert.Equal(t, "abc", tree.Search(Key("abc")))
+	_, ok := tree.Get(Key("averyvery"))
+	assert.False(t, ok)
+
+	for _, k := range keys {
+		assert.True(t, tree.Delete(Key(k)), k)
+		assert.False(t, tree.Delete(Key(k)), k)
+		_, ok := tree.Get(Key(k))
+		assert.False(t, ok, k)
+	}
+
+	assert.Zero(t, tree.Size())
+	assert.Nil(t, tree.root.Load())
+}
+
+func TestConcurrentTreeGrowAndShrink(t *testing.T) {
+	tree := newConcurrentArt[Value]()
+
+	for i := 0; i < 256; i++ {
+		tree.Insert(Key{byte(i)}, i)
+	}
+	assert.Equal(t, Node256, tree.root.Load().nodeType)
+
+	for i := 255; i >= 2; i-- {
+		assert.True(t, tree.Delete(Key{byte(i)}))
+		switch i {
+		case node48Max:
+			assert.Equal(t, Node48, tree.root.Load().nodeType)
+		case node16Max:
+			assert.Equal(t, Node16, tree.root.Load().nodeType)
+		case node4Max:
+			assert.Equal(t, Node4, tree.root.Load().nodeType)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, i, tree.Search(Key{byte(i)}))
+	}
+}
+
+func TestConcurrentTreeChangesInPlace(t *testing.T) {
+	tree := newConcurrentArt[Value]()
+
+	tree.Insert(Key("a"), "a")
+	tree.Insert(Key("b"), "b")
+	root := tree.root.Load()
+
+	// Children are added and removed without copying until the node grows or shrinks.
+	tree.Insert(Key("d"), "d")
+	tree.Insert(Key("c"), "c")
+	tree.Insert(Key(""), "")
+	assert.True(t, tree.Delete(Key("d")))
+	assert.Same(t, root, tree.root.Load())
+	assert.Equal(t, "c", tree.Search(Key("c")))
+
+	tree.Insert(Key("d"), "d")
+	tree.Insert(Key("e"), "e")
+	assert.NotSame(t, root, tree.root.Load())
+	assert.Equal(t, Node16, tree.root.Load().nodeType)
+	for _, k := range []string{"", "a", "b", "c", "d", "e"} {
+		assert.Equal(t, k, tree.Search(Key(k)))
+	}
+}
+
+func TestConcurrentTreeEachManyWords(t *testing.T) {
+	tree := newConcurrentArt[Value]()
+
+	words := testdata.LoadTestFile("testdata/data/words.txt")
+	for _, w := range words {
+		tree.Insert(w, w)
+	}
+
+	var prev Key
+	leafCount := 0
+	tree.Each(func(node TypedNode[Value]) {
+		if node.NodeType() == LeafNode {
+			assert.True(t, bytes.Compare(prev, node.Key()) < 0)
+			prev = node.Key()
+			leafCount++
+		}
+	})
+	assert.Equal(t, len(words), leafCount)
+	assert.Equal(t, len(words), tree.Size())
+}
+
+func TestConcurrentTreeParallelWriters(t *testing.T) {
+	tree := NewConcurrent[int]()
+
+	words := testdata.LoadTestFile("testdata/data/words.txt")
+	if testing.Short() {
+		words = words[:20000]
+	}
+	const workers = 8
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(words); i += workers {
+				tree.Insert(words[i], i)
+			}
+		}(w)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(words); i += workers {
+				if value, ok := tree.Get(words[i]); ok {
+					assert.Equal(t, i, value)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	assert.Equal(t, len(words), tree.Size())
+	for i, w := range words {
+		value, ok := tree.Get(w)
+		if !assert.True(t, ok, string(w)) {
+			return
+		}
+		assert.Equal(t, i, value)
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(words); i += workers {
+				if i%2 == 0 {
+					assert.True(t, tree.Delete(words[i]))
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	assert.Equal(t, len(words)/2, tree.Size())
+	for i, w := range words {
+		_, ok := tree.Get(w)
+		assert.Equal(t, i%2 == 1, ok, string(w))
+	}
+}
+
+func TestConcurrentTreeEmptyKey(t *testing.T) {
+	tree := newConcurrentArt[Value]()
+
+	tree.Insert(Key(""), "root")
+	assert.Equal(t, "root", tree.Search(Key("")))
+	tree.Insert(Key("a"), "a")
+	tree.Insert(Key("b"), "b")
+	assert.Equal(t, 3, tree.Size())
+	assert.Equal(t, "root", tree.Search(nil))
+
+	var keys []string
+	tree.Each(func(node TypedNode[Value]) {
+		if node.NodeType() == LeafNode {
+			keys = append(keys, string(node.Key()))
+		}
+	})
+	assert.Equal(t, []string{"", "a", "b"}, keys)
+
+	assert.True(t, tree.Delete(Key("")))
+	assert.False(t, tree.Delete(Key("")))
+	assert.Equal(t, 2, tree.Size())
+	assert.Equal(t, "a", tree.Search(Key("a")))
+}
+
+// rwMutexTree guards a tree with a sync.RWMutex, as the baseline for the concurrent tree.
+type rwMutexTree[V any] struct {
+	mu   sync.RWMutex
+	tree *tree[V]
+}
+
+func (t *rwMutexTree[V]) Insert(key Key, value V) {
+	t.mu.Lock()
+	t.tree.Insert(key, value)
+	t.mu.Unlock()
+}
+
+func (t *rwMutexTree[V]) Delete(key Key) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.tree.Delete(key)
+}
+
+func (t *rwMutexTree[V]) Get(key Key) (V, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tree.Get(key)
+}
+
+// mixedTree is the part of a tree exercised by benchmarkWordsMixed.
+type mixedTree interface {
+	Insert(key Key, value int)
+	Delete(key Key) bool
+	Get(key Key) (int, bool)
+}
+
+func BenchmarkWordsConcurrentMixed(b *testing.B) {
+	words := testdata.LoadTestFile("testdata/data/words.txt")
+	b.Run("OLC", func(b *testing.B) {
+		benchmarkWordsMixed(b, newConcurrentArt[int](), words)
+	})
+	b.Run("RWMutex", func(b *testing.B) {
+		benchmarkWordsMixed(b, &rwMutexTree[int]{tree: newArt[int]()}, words)
+	})
+}
+
+// benchmarkWordsMixed runs 90% lookups, 5% inserts and 5% deletes of words in parallel.
+func benchmarkWordsMixed(b *testing.B, tree mixedTree, words []Key) {
+	for i, w := range words {
+		if i%2 == 0 {
+			tree.Insert(w, i)
+		}
+	}
+	var seed atomic.Int64
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := int(seed.Add(1)) * 7919
+		for pb.Next() {
+			w := words[i%len(words)]
+			switch i % 20 {
+			case 0:
+				tree.Insert(w, i)
+			case 1:
+				tree.Delete(w)
+			default:
+				tree.Get(w)
+			}
+			i++
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package art
+
+import "bytes"
+
+// cursorFrame is an inner node on the path to the current leaf,
+// together with the position of the child being visited.
+type cursorFrame[V any] struct {
+	node *artNode[V]
+	pos  int
+}
+
+// cursor - adaptive radix tree cursor type.
+type cursor[V any] struct {
+	tree  *tree[V]
+	stack []cursorFrame[V]
+	leaf  *artNode[V]
+}
+
+// Cursor returns a new cursor over the tree, which is not positioned on any leaf.
+func (t *tree[V]) Cursor() TypedCursor[V] {
+	return &cursor[V]{tree: t}
+}
+
+// First moves the cursor to the smallest key in the tree.
+func (c *cursor[V]) First() bool {
+	c.reset()
+	return c.descendFirst(c.tree.root)
+}
+
+// Last moves the cursor to the largest key in the tree.
+func (c *cursor[V]) Last() bool {
+	c.reset()
+	return c.descendLast(c.tree.root)
+}
+
+// Seek moves the cursor to the smallest key that is greater than or equal to the passed in key.
+func (c *cursor[V]) Seek(key Key) bool {
+	c.reset()
+
+	current := c.tree.root
+	depth := 0
+	for current != nil {
+		if current.isLeaf() {
+			c.leaf = current
+			if bytes.Compare(current.leafNode().key, key) >= 0 {
+				return true
+			}
+			return c.Next()
+		}
+
+		switch cmp := current.comparePrefix(key, depth); {
+		case cmp > 0:
+			return c.descendFirst(current)
+		case cmp < 0:
+			return c.ascendNext()
+		}
+		depth += current.node().prefixLen
+
+		if depth >= len(key) {
+			return c.descendFirst(current)
+		}
+
+		pos, child := current.nextChild(current.childPos(key[depth]))
+		if child == nil {
+			return c.ascendNext()
+		}
+		c.stack = append(c.stack, cursorFrame[V]{node: current, pos: pos})
+		if current.keyAt(pos) != key[depth] {
+			return c.descendFirst(child)
+		}
+		current = child
+		depth++
+	}
+
+	return false
+}
+
+// Next moves the cursor to the next key in the tree.
+func (c *cursor[V]) Next() bool {
+	if c.leaf == nil {
+		return false
+	}
+	return c.ascendNext()
+}
+
+// Prev moves the cursor to the previous key in the tree.
+func (c *cursor[V]) Prev() bool {
+	if c.leaf == nil {
+		return false
+	}
+	return c.ascendPrev()
+}
+
+// Valid returns whether the cursor is positioned on a leaf.
+func (c *cursor[V]) Valid() bool {
+	return c.leaf != nil
+}
+
+// Key returns the key of the current leaf, or nil if the cursor is not valid.
+func (c *cursor[V]) Key() Key {
+	if c.leaf == nil {
+		return nil
+	}
+	return c.leaf.leafNode().key
+}
+
+// Value returns the value of the current leaf, or the zero value if the cursor is not valid.
+func (c *cursor[V]) Value() V {
+	if c.leaf == nil {
+		var zero V
+		return zero
+	}
+	return c.leaf.leafNode().value
+}
+
+// reset clears the position of the cursor.
+func (c *cursor[V]) reset() {
+	c.stack = c.stack[:0]
+	c.leaf = nil
+}
+
+// descendFirst moves the cursor to the smallest leaf below the passed in artNode.
+func (c *cursor[V]) descendFirst(current *artNode[V]) bool {
+	for current != nil && !current.isLeaf() {
+		pos, child := current.nextChild(terminalPos)
+		c.stack = append(c.stack, cursorFrame[V]{node: current, pos: pos})
+		current = child
+	}
+	c.leaf = current
+	return c.leaf != nil
+}
+
+// descendLast moves the cursor to the largest leaf below the passed in artNode.
+func (c *cursor[V]) descendLast(current *artNode[V]) bool {
+	for current != nil && !current.isLeaf() {
+		pos, child := current.prevChild(node256Max - 1)
+		c.stack = append(c.stack, cursorFrame[V]{node: current, pos: pos})
+		current = child
+	}
+	c.leaf = current
+	return c.leaf != nil
+}
+
+// ascendNext moves the cursor to the smallest leaf after the subtrees
+// currently visited by the frames on the stack.
+func (c *cursor[V]) ascendNext() bool {
+	for len(c.stack) > 0 {
+		top := &c.stack[len(c.stack)-1]
+		if pos, child := top.node.nextChild(top.pos + 1); child != nil {
+			top.pos = pos
+			return c.descendFirst(child)
+		}
+		c.stack = c.stack[:len(c.stack)-1]
+	}
+	c.leaf = nil
+	return false
+}
+
+// ascendPrev moves the cursor to the largest leaf before the subtrees
+// currently visited by the frames on the stack.
+func (c *cursor[V]) ascendPrev() bool {
+	for len(c.stack) > 0 {
+		top := &c.stack[len(c.stack)-1]
+		if pos, child := top.node.prevChild(top.pos - 1); child != nil {
+			top.pos = pos
+			return c.descendLast(child)
+		}
+		c.stack = c.stack[:len(c.stack)-1]
+	}
+	c.leaf = nil
+	return false
+}
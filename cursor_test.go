@@ -0,0 +1,188 @@
+package art
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"art/testdata"
+)
+
+func TestCursorEmptyTree(t *testing.T) {
+	c := newArt[Value]().Cursor()
+
+	assert.False(t, c.First())
+	assert.False(t, c.Last())
+	assert.False(t, c.Seek(Key("a")))
+	assert.False(t, c.Valid())
+	assert.Nil(t, c.Key())
+	assert.Nil(t, c.Value())
+}
+
+func TestCursorSingleLeaf(t *testing.T) {
+	tree := newArt[Value]()
+	tree.Insert(Key("hello"), "world")
+	c := tree.Cursor()
+
+	assert.True(t, c.First())
+	assert.Equal(t, Key("hello"), c.Key())
+	assert.Equal(t, "world", c.Value())
+	assert.False(t, c.Next())
+	assert.False(t, c.Valid())
+
+	assert.True(t, c.Seek(Key("a")))
+	assert.Equal(t, Key("hello"), c.Key())
+	assert.False(t, c.Seek(Key("hellp")))
+}
+
+func TestCursorSeekNextPrev(t *testing.T) {
+	tree := newArt[Value]()
+	for _, k := range []string{"a", "aa", "ab", "abc", "b", "ba"} {
+		tree.Insert(Key(k), k)
+	}
+	c := tree.Cursor()
+
+	assert.True(t, c.Seek(Key("ab")))
+	assert.Equal(t, Key("ab"), c.Key())
+	assert.True(t, c.Next())
+	assert.Equal(t, Key("abc"), c.Key())
+	assert.True(t, c.Prev())
+	assert.Equal(t, Key("ab"), c.Key())
+	assert.True(t, c.Prev())
+	assert.Equal(t, Key("aa"), c.Key())
+
+	assert.True(t, c.Seek(Key("abb")))
+	assert.Equal(t, Key("abc"), c.Key())
+
+	assert.True(t, c.Seek(Key("abd")))
+	assert.Equal(t, Key("b"), c.Key())
+
+	assert.True(t, c.Seek(Key("")))
+	assert.Equal(t, Key("a"), c.Key())
+	assert.False(t, c.Prev())
+
+	assert.False(t, c.Seek(Key("bb")))
+}
+
+func TestCursorIterateNode48AndNode256(t *testing.T) {
+	for _, total := range []int{30, 200} {
+		tree := newArt[Value]()
+		for i := total - 1; i >= 0; i-- {
+			tree.Insert(Key{byte(i), 'x'}, i)
+		}
+		c := tree.Cursor()
+
+		i := 0
+		for ok := c.First(); ok; ok = c.Next() {
+			assert.Equal(t, Key{byte(i), 'x'}, c.Key())
+			i++
+		}
+		assert.Equal(t, total, i)
+
+		for ok := c.Last(); ok; ok = c.Prev() {
+			i--
+			assert.Equal(t, i, c.Value())
+		}
+		assert.Zero(t, i)
+
+		assert.True(t, c.Seek(Key{byte(total / 2)}))
+		assert.Equal(t, total/2, c.Value())
+	}
+}
+
+func TestCursorManyWords(t *testing.T) {
+	tree := newArt[Value]()
+
+	words := testdata.LoadTestFile("testdata/data/words.txt")
+	for _, w := range words {
+		tree.Insert(w, w)
+	}
+	sorted := make([][]byte, len(words))
+	copy(sorted, words)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i], sorted[j]) < 0
+	})
+
+	c := tree.Cursor()
+	i := 0
+	for ok := c.First(); ok; ok = c.Next() {
+		if !assert.Equal(t, sorted[i], c.Key()) {
+			return
+		}
+		i++
+	}
+	assert.Equal(t, len(sorted), i)
+
+	for ok := c.Last(); ok; ok = c.Prev() {
+		i--
+		if !assert.Equal(t, sorted[i], c.Key()) {
+			return
+		}
+	}
+	assert.Zero(t, i)
+
+	for i := 0; i < len(sorted); i += 997 {
+		assert.True(t, c.Seek(sorted[i]))
+		assert.Equal(t, sorted[i], c.Key())
+	}
+}
+
+func TestMinMaxFloorCeiling(t *testing.T) {
+	tree := newArt[Value]()
+
+	_, _, ok := tree.Min()
+	assert.False(t, ok)
+	_, _, ok = tree.Max()
+	assert.False(t, ok)
+	_, _, ok = tree.Floor(Key("a"))
+	assert.False(t, ok)
+	_, _, ok = tree.Ceiling(Key("a"))
+	assert.False(t, ok)
+
+	for _, k := range []string{"b", "d", "da", "f"} {
+		tree.Insert(Key(k), k)
+	}
+
+	key, value, ok := tree.Min()
+	assert.True(t, ok)
+	assert.Equal(t, Key("b"), key)
+	assert.Equal(t, "b", value)
+
+	key, value, ok = tree.Max()
+	assert.True(t, ok)
+	assert.Equal(t, Key("f"), key)
+	assert.Equal(t, "f", value)
+
+	var testData = []struct {
+		key     string
+		floor   string
+		ceiling string
+	}{
+		{"a", "", "b"},
+		{"b", "b", "b"},
+		{"c", "b", "d"},
+		{"d", "d", "d"},
+		{"d0", "d", "da"},
+		{"db", "da", "f"},
+		{"f", "f", "f"},
+		{"g", "f", ""},
+	}
+
+	for _, data := range testData {
+		key, value, ok = tree.Floor(Key(data.key))
+		assert.Equal(t, data.floor != "", ok, data.key)
+		if ok {
+			assert.Equal(t, Key(data.floor), key, data.key)
+			assert.Equal(t, data.floor, value, data.key)
+		}
+
+		key, value, ok = tree.Ceiling(Key(data.key))
+		assert.Equal(t, data.ceiling != "", ok, data.key)
+		if ok {
+			assert.Equal(t, Key(data.ceiling), key, data.key)
+			assert.Equal(t, data.ceiling, value, data.key)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package art
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Dump writes the internal structure of the tree to the passed in writer in the passed in format.
+// It shows node types, compressed paths, child key bytes, and leaf keys and values.
+// The terminal leafNode of an inner node is shown under the $ label.
+func (t *tree[V]) Dump(w io.Writer, format DumpFormat) error {
+	d := &dumper[V]{w: bufio.NewWriter(w)}
+	switch format {
+	case DumpText:
+		if t.root != nil {
+			d.text(t.root, 0)
+		}
+	case DumpDOT:
+		d.w.WriteString("digraph art {\n")
+		if t.root != nil {
+			d.dot(t.root)
+		}
+		d.w.WriteString("}\n")
+	default:
+		return fmt.Errorf("art: unknown dump format %d", format)
+	}
+	return d.w.Flush()
+}
+
+// dumper writes the nodes of a tree for Dump.
+type dumper[V any] struct {
+	w   *bufio.Writer
+	ids int
+}
+
+// text writes the passed in artNode and its subtree as an indented outline.
+func (d *dumper[V]) text(current *artNode[V], indent int) {
+	d.w.WriteString(describe(current))
+	d.w.WriteByte('\n')
+	if current.isLeaf() {
+		return
+	}
+
+	for pos, child := current.nextChild(terminalPos); child != nil; pos, child = current.nextChild(pos + 1) {
+		d.w.WriteString(strings.Repeat("  ", indent+1))
+		fmt.Fprintf(d.w, "%s: ", childLabel(current, pos))
+		d.text(child, indent+1)
+	}
+}
+
+// dot writes the passed in artNode and its subtree as DOT statements,
+// and returns the identifier of the artNode.
+func (d *dumper[V]) dot(current *artNode[V]) int {
+	id := d.ids
+	d.ids++
+
+	shape := "ellipse"
+	if current.isLeaf() {
+		shape = "box"
+	}
+	fmt.Fprintf(d.w, "  n%d [shape=%s, label=\"%s\"];\n", id, shape, dotEscape(describe(current)))
+	if current.isLeaf() {
+		return id
+	}
+
+	for pos, child := current.nextChild(terminalPos); child != nil; pos, child = current.nextChild(pos + 1) {
+		childID := d.dot(child)
+		fmt.Fprintf(d.w, "  n%d -> n%d [label=\"%s\"];\n", id, childID, dotEscape(childLabel(current, pos)))
+	}
+	return id
+}
+
+// describe returns a one line description of the passed in artNode.
+func describe[V any](n *artNode[V]) string {
+	if n.isLeaf() {
+		leaf := n.leafNode()
+		return fmt.Sprintf("%v key=%q value=%v", n.nodeType, leaf.key, leaf.value)
+	}
+	node := n.node()
+	return fmt.Sprintf("%v size=%d prefixLen=%d prefix=%q", n.nodeType, node.size, node.prefixLen, node.prefix[:min(node.prefixLen, maxPrefixLen)])
+}
+
+// childLabel returns the label of the child of the passed in artNode at the specified position,
+// which is $ for the terminal leafNode and the quoted key byte otherwise.
+func childLabel[V any](n *artNode[V], pos int) string {
+	if pos == terminalPos {
+		return "$"
+	}
+	return fmt.Sprintf("%q", []byte{n.keyAt(pos)})
+}
+
+// dotEscape escapes the passed in string to be used inside a quoted DOT string.
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,81 @@
+package art
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func dumpTestTree() *tree[Value] {
+	tree := newArt[Value]()
+	for i, key := range []string{"abc/1", "abc/2", "abd"} {
+		tree.Insert(Key(key), i)
+	}
+	return tree
+}
+
+func TestDumpText(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, dumpTestTree().Dump(&buf, DumpText))
+
+	expected := strings.Join([]string{
+		`Node4 size=2 prefixLen=2 prefix="ab"`,
+		`  "c": Node4 size=2 prefixLen=1 prefix="/"`,
+		`    "1": Leaf key="abc/1" value=0`,
+		`    "2": Leaf key="abc/2" value=1`,
+		`  "d": Leaf key="abd" value=2`,
+		``,
+	}, "\n")
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestDumpDOT(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, dumpTestTree().Dump(&buf, DumpDOT))
+
+	expected := strings.Join([]string{
+		`digraph art {`,
+		`  n0 [shape=ellipse, label="Node4 size=2 prefixLen=2 prefix=\"ab\""];`,
+		`  n1 [shape=ellipse, label="Node4 size=2 prefixLen=1 prefix=\"/\""];`,
+		`  n2 [shape=box, label="Leaf key=\"abc/1\" value=0"];`,
+		`  n1 -> n2 [label="\"1\""];`,
+		`  n3 [shape=box, label="Leaf key=\"abc/2\" value=1"];`,
+		`  n1 -> n3 [label="\"2\""];`,
+		`  n0 -> n1 [label="\"c\""];`,
+		`  n4 [shape=box, label="Leaf key=\"abd\" value=2"];`,
+		`  n0 -> n4 [label="\"d\""];`,
+		`}`,
+		``,
+	}, "\n")
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestDumpEmptyTree(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, newArt[Value]().Dump(&buf, DumpText))
+	assert.Empty(t, buf.String())
+
+	assert.NoError(t, newArt[Value]().Dump(&buf, DumpDOT))
+	assert.Equal(t, "digraph art {\n}\n", buf.String())
+
+	assert.Error(t, newArt[Value]().Dump(&buf, DumpFormat(42)))
+}
+
+func TestDumpTextTerminalLeaf(t *testing.T) {
+	tree := newArt[Value]()
+	tree.Insert(Key("ab"), 0)
+	tree.Insert(Key("abc"), 1)
+
+	var buf bytes.Buffer
+	assert.NoError(t, tree.Dump(&buf, DumpText))
+
+	expected := strings.Join([]string{
+		`Node4 size=1 prefixLen=2 prefix="ab"`,
+		`  $: Leaf key="ab" value=0`,
+		`  "c": Leaf key="abc" value=1`,
+		``,
+	}, "\n")
+	assert.Equal(t, expected, buf.String())
+}
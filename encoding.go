@@ -0,0 +1,305 @@
+package art
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// encodingMagic starts every encoded tree.
+var encodingMagic = [3]byte{'A', 'R', 'T'}
+
+// encodingVersion is the version of the encoding written by Save.
+const encodingVersion = 1
+
+// maxDecodeDepth is the maximum nesting of the nodes read by Load.
+const maxDecodeDepth = 1 << 16
+
+// Errors returned by Load.
+var (
+	ErrInvalidEncoding     = errors.New("art: invalid tree encoding")
+	ErrUnsupportedEncoding = errors.New("art: unsupported tree encoding version")
+)
+
+// Codec - encodes and decodes the values of a tree storing values of type V.
+type Codec[V any] interface {
+	MarshalValue(value V) ([]byte, error)
+	UnmarshalValue(data []byte) (V, error)
+}
+
+// GobCodec - Codec encoding values with encoding/gob.
+// Concrete types stored in interface values must be registered with gob.Register.
+type GobCodec[V any] struct{}
+
+// MarshalValue encodes the passed in value with encoding/gob.
+func (GobCodec[V]) MarshalValue(value V) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalValue decodes a value encoded with encoding/gob.
+func (GobCodec[V]) UnmarshalValue(data []byte) (V, error) {
+	var value V
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&value)
+	return value, err
+}
+
+// Save writes the tree to the passed in writer, encoding values with the passed in codec.
+// The encoding keeps node types and compressed paths, so that Load restores the same structure.
+func (t *tree[V]) Save(w io.Writer, codec Codec[V]) error {
+	e := &encoder[V]{w: bufio.NewWriter(w), codec: codec}
+	e.w.Write(encodingMagic[:])
+	e.w.WriteByte(encodingVersion)
+	e.writeUvarint(uint64(t.size))
+	if t.root != nil {
+		if err := e.encode(t.root); err != nil {
+			return err
+		}
+	}
+	return e.w.Flush()
+}
+
+// Load reads a tree written by Save from the passed in reader, decoding values with the passed in codec.
+// The structure read is checked like Validate does, and rejected with ErrInvalidEncoding if broken.
+func Load[V any](r io.Reader, codec Codec[V]) (TypedTree[V], error) {
+	d := &decoder[V]{r: bufio.NewReader(r), codec: codec}
+
+	var header [len(encodingMagic) + 1]byte
+	if _, err := io.ReadFull(d.r, header[:]); err != nil {
+		return nil, d.wrap(err)
+	}
+	if !bytes.Equal(header[:len(encodingMagic)], encodingMagic[:]) {
+		return nil, ErrInvalidEncoding
+	}
+	if version := header[len(encodingMagic)]; version != encodingVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedEncoding, version)
+	}
+
+	size, err := d.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	t := newArt[V]()
+	if size > 0 {
+		if t.root, err = d.decode(0); err != nil {
+			return nil, err
+		}
+	}
+	if d.leaves != size {
+		return nil, fmt.Errorf("%w: expected %d leaves, found %d", ErrInvalidEncoding, size, d.leaves)
+	}
+	t.size = int64(size)
+
+	if err := t.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+	}
+	return t, nil
+}
+
+// encoder writes the nodes of a tree in pre-order.
+type encoder[V any] struct {
+	w     *bufio.Writer
+	codec Codec[V]
+	buf   [binary.MaxVarintLen64]byte
+}
+
+// encode writes the passed in artNode and its subtree.
+func (e *encoder[V]) encode(current *artNode[V]) error {
+	e.w.WriteByte(byte(current.nodeType))
+
+	if current.isLeaf() {
+		leaf := current.leafNode()
+		value, err := e.codec.MarshalValue(leaf.value)
+		if err != nil {
+			return err
+		}
+		e.writeBytes(leaf.key)
+		e.writeBytes(value)
+		return nil
+	}
+
+	node := current.node()
+	e.writeUvarint(uint64(node.prefixLen))
+	e.w.Write(node.prefix[:min(node.prefixLen, maxPrefixLen)])
+	if node.terminal != nil {
+		e.w.WriteByte(1)
+		if err := e.encode(node.terminal); err != nil {
+			return err
+		}
+	} else {
+		e.w.WriteByte(0)
+	}
+	e.writeUvarint(uint64(node.size))
+	for pos, child := current.nextChild(0); child != nil; pos, child = current.nextChild(pos + 1) {
+		e.w.WriteByte(current.keyAt(pos))
+		if err := e.encode(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeUvarint writes the passed in number as uvarint.
+func (e *encoder[V]) writeUvarint(x uint64) {
+	n := binary.PutUvarint(e.buf[:], x)
+	e.w.Write(e.buf[:n])
+}
+
+// writeBytes writes the passed in bytes prefixed with their length.
+func (e *encoder[V]) writeBytes(b []byte) {
+	e.writeUvarint(uint64(len(b)))
+	e.w.Write(b)
+}
+
+// decoder reads the nodes of a tree written by encoder.
+type decoder[V any] struct {
+	r      *bufio.Reader
+	codec  Codec[V]
+	leaves uint64
+}
+
+// decode reads an artNode and its subtree, found at the passed in nesting depth.
+// The children of every inner node are checked as soon as they are read,
+// so that the subtrees below are well-formed when Load validates their keys.
+func (d *decoder[V]) decode(depth int) (*artNode[V], error) {
+	if depth > maxDecodeDepth {
+		return nil, fmt.Errorf("%w: nodes nested deeper than %d", ErrInvalidEncoding, maxDecodeDepth)
+	}
+
+	nodeType, err := d.r.ReadByte()
+	if err != nil {
+		return nil, d.wrap(err)
+	}
+
+	var n *artNode[V]
+	switch NodeType(nodeType) {
+	case LeafNode:
+		key, err := d.readBytes()
+		if err != nil {
+			return nil, err
+		}
+		data, err := d.readBytes()
+		if err != nil {
+			return nil, err
+		}
+		value, err := d.codec.UnmarshalValue(data)
+		if err != nil {
+			return nil, err
+		}
+		d.leaves++
+		return newLeafNode(key, value), nil
+	case Node4:
+		n = newNode4[V]()
+	case Node16:
+		n = newNode16[V]()
+	case Node48:
+		n = newNode48[V]()
+	case Node256:
+		n = newNode256[V]()
+	default:
+		return nil, fmt.Errorf("%w: unknown node type %d", ErrInvalidEncoding, nodeType)
+	}
+
+	prefixLen, err := d.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	if prefixLen > math.MaxInt32 {
+		return nil, fmt.Errorf("%w: prefix length %d", ErrInvalidEncoding, prefixLen)
+	}
+	node := n.node()
+	node.prefixLen = int(prefixLen)
+	if _, err := io.ReadFull(d.r, node.prefix[:min(node.prefixLen, maxPrefixLen)]); err != nil {
+		return nil, d.wrap(err)
+	}
+
+	hasTerminal, err := d.r.ReadByte()
+	if err != nil {
+		return nil, d.wrap(err)
+	}
+	switch hasTerminal {
+	case 0:
+	case 1:
+		if node.terminal, err = d.decode(depth + 1); err != nil {
+			return nil, err
+		}
+		if !node.terminal.isLeaf() {
+			return nil, fmt.Errorf("%w: terminal node of type %v", ErrInvalidEncoding, node.terminal.nodeType)
+		}
+	default:
+		return nil, fmt.Errorf("%w: terminal flag %d", ErrInvalidEncoding, hasTerminal)
+	}
+
+	size, err := d.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	if size > uint64(n.maxSize()) {
+		return nil, fmt.Errorf("%w: %d children in node of type %d", ErrInvalidEncoding, size, nodeType)
+	}
+	var prevKey byte
+	for i := uint64(0); i < size; i++ {
+		key, err := d.r.ReadByte()
+		if err != nil {
+			return nil, d.wrap(err)
+		}
+		// Children are written in strictly increasing key order.
+		if i > 0 && key <= prevKey {
+			return nil, fmt.Errorf("%w: child key %#x after %#x", ErrInvalidEncoding, key, prevKey)
+		}
+		prevKey = key
+		child, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		n.addChild(key, child)
+	}
+	if err := n.validateChildren(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+	}
+	n.countLeaves()
+	return n, nil
+}
+
+// readUvarint reads a number written as uvarint.
+func (d *decoder[V]) readUvarint() (uint64, error) {
+	x, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return 0, d.wrap(err)
+	}
+	return x, nil
+}
+
+// readBytes reads bytes prefixed with their length.
+func (d *decoder[V]) readBytes() ([]byte, error) {
+	n, err := d.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	if n > math.MaxInt32 {
+		return nil, fmt.Errorf("%w: length %d", ErrInvalidEncoding, n)
+	}
+	// Grow as the data arrives rather than trusting the length up front.
+	var buf bytes.Buffer
+	buf.Grow(min(int(n), d.r.Size()))
+	if _, err := io.CopyN(&buf, d.r, int64(n)); err != nil {
+		return nil, d.wrap(err)
+	}
+	return buf.Bytes(), nil
+}
+
+// wrap reports a truncated input as an invalid encoding.
+func (d *decoder[V]) wrap(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return fmt.Errorf("%w: %v", ErrInvalidEncoding, io.ErrUnexpectedEOF)
+	}
+	return err
+}
@@ -0,0 +1,212 @@
+package art
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"art/testdata"
+)
+
+// uint64Codec encodes uint64 values as fixed size big-endian numbers.
+type uint64Codec struct{}
+
+func (uint64Codec) MarshalValue(value uint64) ([]byte, error) {
+	return binary.BigEndian.AppendUint64(nil, value), nil
+}
+
+func (uint64Codec) UnmarshalValue(data []byte) (uint64, error) {
+	if len(data) != 8 {
+		return 0, errors.New("invalid uint64")
+	}
+	return binary.BigEndian.Uint64(data), nil
+}
+
+// nodeTypeCounts returns the number of nodes of each type in the tree.
+func nodeTypeCounts[V any](tree TypedTree[V]) map[NodeType]int {
+	counts := make(map[NodeType]int)
+	tree.Each(func(node TypedNode[V]) {
+		counts[node.NodeType()]++
+	})
+	return counts
+}
+
+func TestSaveAndLoadManyWords(t *testing.T) {
+	tree := newArt[uint64]()
+
+	words := testdata.LoadTestFile("testdata/data/words.txt")
+	for i, w := range words {
+		tree.Insert(w, uint64(i))
+	}
+	for i := 0; i < len(words); i += 3 {
+		tree.Delete(words[i])
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, tree.Save(&buf, uint64Codec{}))
+
+	loaded, err := Load[uint64](&buf, uint64Codec{})
+	assert.NoError(t, err)
+	assert.Equal(t, tree.Size(), loaded.Size())
+	assert.NoError(t, loaded.Validate())
+	assert.Equal(t, nodeTypeCounts[uint64](tree), nodeTypeCounts(loaded))
+
+	for i, w := range words {
+		value, ok := loaded.Get(w)
+		assert.Equal(t, i%3 != 0, ok)
+		if ok {
+			assert.Equal(t, uint64(i), value)
+		}
+	}
+}
+
+func TestSaveAndLoadGobCodec(t *testing.T) {
+	tree := newArt[Value]()
+	tree.Insert(Key("averyveryverylongprefix/1"), "one")
+	tree.Insert(Key("averyveryverylongprefix/2"), "two")
+	tree.Insert(Key("b"), nil)
+
+	var buf bytes.Buffer
+	assert.NoError(t, tree.Save(&buf, GobCodec[Value]{}))
+
+	loaded, err := Load[Value](&buf, GobCodec[Value]{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, loaded.Size())
+	assert.Equal(t, "one", loaded.Search(Key("averyveryverylongprefix/1")))
+	assert.Equal(t, "two", loaded.Search(Key("averyveryverylongprefix/2")))
+	value, ok := loaded.Get(Key("b"))
+	assert.True(t, ok)
+	assert.Nil(t, value)
+}
+
+func TestSaveAndLoadEmptyTree(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, newArt[uint64]().Save(&buf, uint64Codec{}))
+
+	loaded, err := Load[uint64](&buf, uint64Codec{})
+	assert.NoError(t, err)
+	assert.Zero(t, loaded.Size())
+}
+
+func TestLoadInvalidEncoding(t *testing.T) {
+	tree := newArt[uint64]()
+	for i := uint64(0); i < 100; i++ {
+		tree.Insert(binary.BigEndian.AppendUint64(nil, i), i)
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, tree.Save(&buf, uint64Codec{}))
+	data := buf.Bytes()
+
+	_, err := Load[uint64](bytes.NewReader(data[:len(data)-1]), uint64Codec{})
+	assert.True(t, errors.Is(err, ErrInvalidEncoding))
+
+	_, err = Load[uint64](bytes.NewReader([]byte("nope")), uint64Codec{})
+	assert.True(t, errors.Is(err, ErrInvalidEncoding))
+
+	future := append([]byte(nil), data...)
+	future[len(encodingMagic)] = encodingVersion + 1
+	_, err = Load[uint64](bytes.NewReader(future), uint64Codec{})
+	assert.True(t, errors.Is(err, ErrUnsupportedEncoding))
+}
+
+func TestSaveAndLoadKeysWithPrefixes(t *testing.T) {
+	tree := newArt[uint64]()
+	for i, key := range []string{"a", "ab", "ab\x00", "abc", "b"} {
+		tree.Insert(Key(key), uint64(i))
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, tree.Save(&buf, uint64Codec{}))
+
+	loaded, err := Load[uint64](&buf, uint64Codec{})
+	assert.NoError(t, err)
+	assert.NoError(t, loaded.Validate())
+	for i, key := range []string{"a", "ab", "ab\x00", "abc", "b"} {
+		assert.Equal(t, uint64(i), loaded.Search(Key(key)))
+	}
+}
+
+func TestLoadRejectsBrokenStructure(t *testing.T) {
+	leaf := func(key string, value byte) []byte {
+		return append([]byte{byte(LeafNode), byte(len(key))}, append([]byte(key), 8, 0, 0, 0, 0, 0, 0, 0, value)...)
+	}
+	header := func(size byte) []byte {
+		return []byte{'A', 'R', 'T', encodingVersion, size}
+	}
+	concat := func(parts ...[]byte) []byte {
+		var data []byte
+		for _, part := range parts {
+			data = append(data, part...)
+		}
+		return data
+	}
+
+	testCases := map[string][]byte{
+		"terminal only": concat(header(1), []byte{byte(Node4), 0, 1}, leaf("", 1), []byte{0}),
+		"single child":  concat(header(1), []byte{byte(Node4), 0, 0, 1, 'a'}, leaf("a", 1)),
+		"duplicate key bytes": concat(header(2), []byte{byte(Node4), 0, 0, 2},
+			[]byte{'a'}, leaf("a", 1), []byte{'a'}, leaf("a", 2)),
+		"unsorted key bytes": concat(header(2), []byte{byte(Node4), 0, 0, 2},
+			[]byte{'b'}, leaf("b", 1), []byte{'a'}, leaf("a", 2)),
+		"leaf off its path": concat(header(2), []byte{byte(Node4), 1, 'x', 0, 2},
+			[]byte{'a'}, leaf("xa", 1), []byte{'b'}, leaf("yb", 2)),
+		"terminal off its path": concat(header(2), []byte{byte(Node4), 1, 'x', 1}, leaf("y", 1),
+			[]byte{1, 'a'}, leaf("xa", 2)),
+		"undersized Node16": concat(header(2), []byte{byte(Node16), 0, 0, 2},
+			[]byte{'a'}, leaf("a", 1), []byte{'b'}, leaf("b", 2)),
+	}
+	for name, data := range testCases {
+		_, err := Load[uint64](bytes.NewReader(data), uint64Codec{})
+		assert.ErrorIs(t, err, ErrInvalidEncoding, name)
+	}
+
+	// A valid encoding built the same way is accepted.
+	valid := concat(header(2), []byte{byte(Node4), 1, 'x', 0, 2}, []byte{'a'}, leaf("xa", 1), []byte{'b'}, leaf("xb", 2))
+	loaded, err := Load[uint64](bytes.NewReader(valid), uint64Codec{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), loaded.Search(Key("xb")))
+}
+
+func TestLoadRejectsDeepNesting(t *testing.T) {
+	data := []byte{'A', 'R', 'T', encodingVersion, 1}
+	for i := 0; i < maxDecodeDepth+10; i++ {
+		data = append(data, byte(Node4), 0, 0, 1, 'a')
+	}
+
+	_, err := Load[uint64](bytes.NewReader(data), uint64Codec{})
+	assert.ErrorIs(t, err, ErrInvalidEncoding)
+	assert.Contains(t, err.Error(), "nested deeper")
+}
+
+func TestLoadCorruptedBytes(t *testing.T) {
+	tree := newArt[uint64]()
+	for i, key := range []string{"", "a", "ab", "ab\x00", "abc", "averyveryverylongprefix/1", "averyveryverylongprefix/2", "b"} {
+		tree.Insert(Key(key), uint64(i))
+	}
+	for i := 0; i < 20; i++ {
+		tree.Insert(Key{'c', byte(i)}, uint64(i))
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, tree.Save(&buf, uint64Codec{}))
+	data := buf.Bytes()
+
+	for i := range data {
+		for _, c := range []byte{0, 1, 2, 3, 4, 'a', 'c', 0x80, 0xff, data[i] + 1, data[i] - 1} {
+			corrupted := append([]byte(nil), data...)
+			corrupted[i] = c
+			loaded, err := Load[uint64](bytes.NewReader(corrupted), uint64Codec{})
+			if err != nil {
+				// Corrupted values are reported by the codec.
+				continue
+			}
+			if !assert.NoError(t, loaded.Validate(), "byte %d set to %#x", i, c) {
+				return
+			}
+			loaded.Min()
+			loaded.Max()
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package art
+
+import "iter"
+
+// All returns an iterator over the key-value pairs of the tree in lexicographical order.
+func (t *tree[V]) All() iter.Seq2[Key, V] {
+	return t.leaves(t.root, WalkOptions{Nodes: WalkLeaves})
+}
+
+// Backward returns an iterator over the key-value pairs of the tree in reverse lexicographical order.
+func (t *tree[V]) Backward() iter.Seq2[Key, V] {
+	return t.leaves(t.root, WalkOptions{Nodes: WalkLeaves, Descending: true})
+}
+
+// Prefix returns an iterator over the key-value pairs whose key starts with the passed in prefix,
+// in lexicographical order.
+func (t *tree[V]) Prefix(prefix Key) iter.Seq2[Key, V] {
+	return func(yield func(Key, V) bool) {
+		t.leaves(t.prefixHelper(t.root, prefix, 0), WalkOptions{Nodes: WalkLeaves})(yield)
+	}
+}
+
+// Between returns an iterator over the key-value pairs whose key lies between lo and hi,
+// in lexicographical order. The bounds are interpreted as in Range.
+func (t *tree[V]) Between(lo, hi Key, opts RangeOptions) iter.Seq2[Key, V] {
+	return func(yield func(Key, V) bool) {
+		t.rangeHelper(lo, hi, opts, func(leaf *artNode[V]) bool {
+			return yield(leaf.leafNode().key, leaf.leafNode().value)
+		})
+	}
+}
+
+// leaves returns an iterator over the leafNodes below the passed in artNode,
+// visited according to the passed in options.
+func (t *tree[V]) leaves(current *artNode[V], opts WalkOptions) iter.Seq2[Key, V] {
+	return func(yield func(Key, V) bool) {
+		t.walkHelper(current, opts, func(node TypedNode[V]) WalkAction {
+			if !yield(node.Key(), node.Value()) {
+				return WalkStop
+			}
+			return WalkContinue
+		})
+	}
+}
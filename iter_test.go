@@ -0,0 +1,118 @@
+package art
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"art/testdata"
+)
+
+func iterTestTree() *tree[int] {
+	tree := newArt[int]()
+	for i, key := range []string{"a", "ab", "abc", "b", "ba", "c"} {
+		tree.Insert(Key(key), i)
+	}
+	return tree
+}
+
+func TestIterAll(t *testing.T) {
+	tree := newArt[int]()
+	words := testdata.LoadTestFile("testdata/data/words.txt")
+	for i, w := range words {
+		tree.Insert(w, i)
+	}
+
+	sorted := make([][]byte, len(words))
+	copy(sorted, words)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i], sorted[j]) < 0
+	})
+
+	var keys [][]byte
+	for k, v := range tree.All() {
+		keys = append(keys, k)
+		if !assert.Equal(t, k, words[v]) {
+			return
+		}
+	}
+	assert.Equal(t, sorted, keys)
+
+	keys = keys[:0]
+	for k := range tree.Backward() {
+		keys = append(keys, k)
+	}
+	for i := range sorted {
+		if !assert.Equal(t, sorted[len(sorted)-1-i], keys[i]) {
+			return
+		}
+	}
+}
+
+func TestIterEarlyExit(t *testing.T) {
+	tree := iterTestTree()
+
+	var keys []string
+	for k := range tree.All() {
+		if string(k) == "b" {
+			break
+		}
+		keys = append(keys, string(k))
+	}
+	assert.Equal(t, []string{"a", "ab", "abc"}, keys)
+
+	keys = nil
+	for k := range tree.Backward() {
+		keys = append(keys, string(k))
+		if len(keys) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []string{"c", "ba"}, keys)
+}
+
+func TestIterPrefix(t *testing.T) {
+	tree := iterTestTree()
+
+	collect := func(prefix string) []string {
+		var keys []string
+		for k := range tree.Prefix(Key(prefix)) {
+			keys = append(keys, string(k))
+		}
+		return keys
+	}
+
+	assert.Equal(t, []string{"a", "ab", "abc"}, collect("a"))
+	assert.Equal(t, []string{"ab", "abc"}, collect("ab"))
+	assert.Equal(t, []string{"b", "ba"}, collect("b"))
+	assert.Nil(t, collect("d"))
+	assert.Len(t, collect(""), 6)
+}
+
+func TestIterBetween(t *testing.T) {
+	tree := iterTestTree()
+
+	collect := func(lo, hi string, opts RangeOptions) []string {
+		var keys []string
+		for k, v := range tree.Between(Key(lo), Key(hi), opts) {
+			keys = append(keys, string(k))
+			assert.Equal(t, tree.Search(k), v)
+		}
+		return keys
+	}
+
+	assert.Equal(t, []string{"ab", "abc", "b"}, collect("ab", "b", RangeOptions{}))
+	assert.Equal(t, []string{"abc"}, collect("ab", "b", RangeOptions{Lo: Exclusive, Hi: Exclusive}))
+	assert.Equal(t, []string{"a", "ab"}, collect("", "ab", RangeOptions{Lo: Unbounded}))
+
+	var keys []string
+	for k := range tree.Between(nil, nil, RangeOptions{Lo: Unbounded, Hi: Unbounded}) {
+		keys = append(keys, string(k))
+		if len(keys) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []string{"a", "ab"}, keys)
+}
@@ -0,0 +1,166 @@
+// Package keys encodes typed values into binary-comparable art keys,
+// whose byte order matches the logical order of the values they encode.
+package keys
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"art"
+)
+
+// ErrInvalidKey is returned when a key can not be decoded as the requested type.
+var ErrInvalidKey = errors.New("keys: invalid key encoding")
+
+const (
+	// escape introduces an escaped byte or the terminator of a string.
+	escape = 0x00
+	// escapedZero follows escape for a zero byte of a string.
+	escapedZero = 0xff
+	// terminator follows escape at the end of a string.
+	terminator = 0x01
+
+	// timeLen is the length of an encoded time.Time.
+	timeLen = 12
+)
+
+// AppendUint64 appends the encoding of the passed in unsigned integer to dst,
+// as 8 big-endian bytes.
+func AppendUint64(dst art.Key, v uint64) art.Key {
+	return binary.BigEndian.AppendUint64(dst, v)
+}
+
+// AppendInt64 appends the encoding of the passed in signed integer to dst,
+// as 8 big-endian bytes with the sign bit flipped so that negative numbers sort first.
+func AppendInt64(dst art.Key, v int64) art.Key {
+	return AppendUint64(dst, uint64(v)^(1<<63))
+}
+
+// AppendFloat64 appends the encoding of the passed in float to dst, as 8 bytes.
+// Negative numbers have all their bits flipped and positive numbers their sign bit,
+// so that -Inf < negative numbers < 0 < positive numbers < +Inf < NaN.
+// Every NaN is encoded as the same positive NaN, and -0 as +0, as they compare equal.
+func AppendFloat64(dst art.Key, v float64) art.Key {
+	switch {
+	case math.IsNaN(v):
+		v = math.NaN()
+	case v == 0:
+		v = 0
+	}
+	bits := math.Float64bits(v)
+	if bits&(1<<63) != 0 {
+		bits = ^bits
+	} else {
+		bits |= 1 << 63
+	}
+	return AppendUint64(dst, bits)
+}
+
+// AppendBytes appends the encoding of the passed in bytes to dst.
+// Zero bytes are escaped and the encoding is terminated,
+// so that no encoding is a prefix of another and shorter values sort first.
+func AppendBytes(dst art.Key, b []byte) art.Key {
+	for _, c := range b {
+		if c == escape {
+			dst = append(dst, escape, escapedZero)
+		} else {
+			dst = append(dst, c)
+		}
+	}
+	return append(dst, escape, terminator)
+}
+
+// AppendString appends the encoding of the passed in string to dst, see AppendBytes.
+func AppendString(dst art.Key, s string) art.Key {
+	return AppendBytes(dst, []byte(s))
+}
+
+// AppendTime appends the encoding of the passed in time to dst,
+// as its Unix seconds followed by its nanoseconds. The location is not kept.
+func AppendTime(dst art.Key, t time.Time) art.Key {
+	dst = AppendInt64(dst, t.Unix())
+	return binary.BigEndian.AppendUint32(dst, uint32(t.Nanosecond()))
+}
+
+// DecodeUint64 decodes an unsigned integer written by AppendUint64 at the start of the passed in key,
+// and returns it together with the rest of the key.
+func DecodeUint64(key art.Key) (uint64, art.Key, error) {
+	if len(key) < 8 {
+		return 0, nil, fmt.Errorf("%w: %d bytes left for a 64-bit number", ErrInvalidKey, len(key))
+	}
+	return binary.BigEndian.Uint64(key), key[8:], nil
+}
+
+// DecodeInt64 decodes a signed integer written by AppendInt64 at the start of the passed in key,
+// and returns it together with the rest of the key.
+func DecodeInt64(key art.Key) (int64, art.Key, error) {
+	v, rest, err := DecodeUint64(key)
+	if err != nil {
+		return 0, nil, err
+	}
+	return int64(v ^ (1 << 63)), rest, nil
+}
+
+// DecodeFloat64 decodes a float written by AppendFloat64 at the start of the passed in key,
+// and returns it together with the rest of the key.
+func DecodeFloat64(key art.Key) (float64, art.Key, error) {
+	bits, rest, err := DecodeUint64(key)
+	if err != nil {
+		return 0, nil, err
+	}
+	if bits&(1<<63) != 0 {
+		bits &^= 1 << 63
+	} else {
+		bits = ^bits
+	}
+	return math.Float64frombits(bits), rest, nil
+}
+
+// DecodeBytes decodes bytes written by AppendBytes at the start of the passed in key,
+// and returns them together with the rest of the key.
+func DecodeBytes(key art.Key) ([]byte, art.Key, error) {
+	b := make([]byte, 0, len(key))
+	for i := 0; i < len(key); i++ {
+		if key[i] != escape {
+			b = append(b, key[i])
+			continue
+		}
+		if i+1 == len(key) {
+			break
+		}
+		switch key[i+1] {
+		case escapedZero:
+			b = append(b, 0)
+			i++
+		case terminator:
+			return b, key[i+2:], nil
+		default:
+			return nil, nil, fmt.Errorf("%w: invalid escape sequence %#x", ErrInvalidKey, key[i+1])
+		}
+	}
+	return nil, nil, fmt.Errorf("%w: unterminated bytes", ErrInvalidKey)
+}
+
+// DecodeString decodes a string written by AppendString at the start of the passed in key,
+// and returns it together with the rest of the key.
+func DecodeString(key art.Key) (string, art.Key, error) {
+	b, rest, err := DecodeBytes(key)
+	return string(b), rest, err
+}
+
+// DecodeTime decodes a time written by AppendTime at the start of the passed in key,
+// and returns it in UTC together with the rest of the key.
+func DecodeTime(key art.Key) (time.Time, art.Key, error) {
+	if len(key) < timeLen {
+		return time.Time{}, nil, fmt.Errorf("%w: %d bytes left for a time", ErrInvalidKey, len(key))
+	}
+	sec, rest, _ := DecodeInt64(key)
+	nsec := binary.BigEndian.Uint32(rest)
+	if nsec >= uint32(time.Second) {
+		return time.Time{}, nil, fmt.Errorf("%w: %d nanoseconds", ErrInvalidKey, nsec)
+	}
+	return time.Unix(sec, int64(nsec)).UTC(), rest[4:], nil
+}
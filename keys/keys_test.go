@@ -0,0 +1,174 @@
+package keys
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"art"
+)
+
+// assertOrdered checks that the passed in keys, encoding values in increasing order, are strictly increasing.
+func assertOrdered(t *testing.T, keys []art.Key) {
+	for i := 1; i < len(keys); i++ {
+		assert.Negative(t, bytes.Compare(keys[i-1], keys[i]), "%x >= %x", keys[i-1], keys[i])
+	}
+}
+
+func TestUint64(t *testing.T) {
+	values := []uint64{0, 1, 255, 256, 1 << 32, math.MaxUint64 - 1, math.MaxUint64}
+
+	var keys []art.Key
+	for _, v := range values {
+		key := AppendUint64(nil, v)
+		decoded, rest, err := DecodeUint64(key)
+		assert.NoError(t, err)
+		assert.Equal(t, v, decoded)
+		assert.Empty(t, rest)
+		keys = append(keys, key)
+	}
+	assertOrdered(t, keys)
+}
+
+func TestInt64(t *testing.T) {
+	values := []int64{math.MinInt64, math.MinInt64 + 1, -256, -1, 0, 1, 255, math.MaxInt64}
+
+	var keys []art.Key
+	for _, v := range values {
+		key := AppendInt64(nil, v)
+		decoded, rest, err := DecodeInt64(key)
+		assert.NoError(t, err)
+		assert.Equal(t, v, decoded)
+		assert.Empty(t, rest)
+		keys = append(keys, key)
+	}
+	assertOrdered(t, keys)
+}
+
+func TestFloat64(t *testing.T) {
+	values := []float64{
+		math.Inf(-1), -math.MaxFloat64, -1.5, -1, -math.SmallestNonzeroFloat64,
+		0, math.SmallestNonzeroFloat64, 1, 1.5, math.MaxFloat64, math.Inf(1),
+	}
+
+	var keys []art.Key
+	for _, v := range values {
+		key := AppendFloat64(nil, v)
+		decoded, rest, err := DecodeFloat64(key)
+		assert.NoError(t, err)
+		assert.Equal(t, math.Float64bits(v), math.Float64bits(decoded))
+		assert.Empty(t, rest)
+		keys = append(keys, key)
+	}
+	assertOrdered(t, keys)
+
+	decoded, _, err := DecodeFloat64(AppendFloat64(nil, math.NaN()))
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(decoded))
+}
+
+func TestFloat64NaNAndZero(t *testing.T) {
+	inf := math.Inf(1)
+	zero := 0.0
+	nans := []float64{
+		math.NaN(),
+		inf - inf,
+		zero / zero,
+		math.Float64frombits(0xfff8000000000000),
+		math.Float64frombits(0x7ff0000000000001),
+		math.Float64frombits(0xffffffffffffffff),
+	}
+	for _, nan := range nans {
+		assert.True(t, math.IsNaN(nan))
+		key := AppendFloat64(nil, nan)
+		assert.Equal(t, AppendFloat64(nil, math.NaN()), key, "%x", math.Float64bits(nan))
+		assert.Positive(t, bytes.Compare(key, AppendFloat64(nil, math.Inf(1))))
+	}
+
+	negativeZero := math.Copysign(0, -1)
+	assert.Equal(t, AppendFloat64(nil, 0), AppendFloat64(nil, negativeZero))
+	decoded, _, err := DecodeFloat64(AppendFloat64(nil, negativeZero))
+	assert.NoError(t, err)
+	assert.False(t, math.Signbit(decoded))
+	assert.Negative(t, bytes.Compare(AppendFloat64(nil, -math.SmallestNonzeroFloat64), AppendFloat64(nil, negativeZero)))
+}
+
+func TestRandomNumbersOrder(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	ints := make([]int64, 1000)
+	floats := make([]float64, 1000)
+	for i := range ints {
+		ints[i] = rnd.Int63() - rnd.Int63()
+		floats[i] = rnd.NormFloat64() * math.Pow(10, float64(rnd.Intn(40)-20))
+	}
+	sort.Slice(ints, func(i, j int) bool { return ints[i] < ints[j] })
+	sort.Float64s(floats)
+
+	for i := 1; i < len(ints); i++ {
+		assert.Equal(t, ints[i-1] < ints[i], bytes.Compare(AppendInt64(nil, ints[i-1]), AppendInt64(nil, ints[i])) < 0)
+		assert.Equal(t, floats[i-1] < floats[i], bytes.Compare(AppendFloat64(nil, floats[i-1]), AppendFloat64(nil, floats[i])) < 0)
+	}
+}
+
+func TestString(t *testing.T) {
+	values := []string{"", "\x00", "\x00\x00", "\x00\x01", "\x00\xff", "\x01", "a", "a\x00", "a\x00b", "ab", "b", "\xff", "\xff\xff"}
+
+	var keys []art.Key
+	for _, v := range values {
+		key := AppendString(nil, v)
+		decoded, rest, err := DecodeString(append(key, 'x'))
+		assert.NoError(t, err)
+		assert.Equal(t, v, decoded)
+		assert.Equal(t, art.Key("x"), rest)
+		keys = append(keys, key)
+	}
+	assertOrdered(t, keys)
+}
+
+func TestBytesInvalid(t *testing.T) {
+	_, _, err := DecodeBytes(art.Key("abc"))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	_, _, err = DecodeBytes(art.Key("ab\x00"))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	_, _, err = DecodeBytes(art.Key("ab\x00\x02"))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	_, _, err = DecodeUint64(art.Key("short"))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestTime(t *testing.T) {
+	base := time.Date(2024, 2, 29, 12, 30, 0, 0, time.UTC)
+	values := []time.Time{
+		time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Unix(-1, 999999999).UTC(),
+		time.Unix(0, 0).UTC(),
+		base,
+		base.Add(time.Nanosecond),
+		base.Add(time.Second),
+		time.Date(9999, 12, 31, 23, 59, 59, 999999999, time.UTC),
+	}
+
+	var keys []art.Key
+	for _, v := range values {
+		key := AppendTime(nil, v)
+		decoded, rest, err := DecodeTime(key)
+		assert.NoError(t, err)
+		assert.True(t, v.Equal(decoded), "%v != %v", v, decoded)
+		assert.Equal(t, time.UTC, decoded.Location())
+		assert.Empty(t, rest)
+		keys = append(keys, key)
+	}
+	assertOrdered(t, keys)
+
+	// The location does not change the order.
+	paris := time.FixedZone("CET", 3600)
+	assert.Equal(t, AppendTime(nil, base), AppendTime(nil, base.In(paris)))
+}
@@ -0,0 +1,209 @@
+package keys
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"art"
+)
+
+// ErrUnsupportedType is returned for tuple columns of a type that can not be encoded.
+var ErrUnsupportedType = errors.New("keys: unsupported column type")
+
+// Order - sort order of a tuple column.
+type Order uint8
+
+// Sort orders.
+const (
+	Ascending Order = iota
+	Descending
+)
+
+// Column - value of a tuple column together with its sort order.
+// Supported values are signed and unsigned integers, floats, strings, byte slices and time.Time.
+// Integers are encoded on 64 bits and float32 as float64, whatever their type.
+type Column struct {
+	Value interface{}
+	Order Order
+}
+
+// Asc returns an ascending column holding the passed in value.
+func Asc(value interface{}) Column {
+	return Column{Value: value, Order: Ascending}
+}
+
+// Desc returns a descending column holding the passed in value.
+func Desc(value interface{}) Column {
+	return Column{Value: value, Order: Descending}
+}
+
+// EncodeTuple encodes the passed in columns into a single key,
+// which sorts by the first column, then by the second one and so on.
+func EncodeTuple(columns ...Column) (art.Key, error) {
+	return AppendTuple(nil, columns...)
+}
+
+// AppendTuple appends the encoding of the passed in columns to dst, see EncodeTuple.
+// A descending column is encoded as the complement of its ascending encoding.
+func AppendTuple(dst art.Key, columns ...Column) (art.Key, error) {
+	for _, column := range columns {
+		start := len(dst)
+		var err error
+		if dst, err = appendValue(dst, column.Value); err != nil {
+			return nil, err
+		}
+		if column.Order == Descending {
+			invert(dst[start:])
+		}
+	}
+	return dst, nil
+}
+
+// DecodeTuple decodes the columns of a key written by EncodeTuple into the values of the passed in columns,
+// which must be pointers to the types of the encoded values, in the same order.
+// It returns the rest of the key, so that leading columns may be decoded alone.
+func DecodeTuple(key art.Key, columns ...Column) (art.Key, error) {
+	for _, column := range columns {
+		encoded := key
+		if column.Order == Descending {
+			encoded = append(art.Key(nil), key...)
+			invert(encoded)
+		}
+		rest, err := decodeValue(encoded, column.Value)
+		if err != nil {
+			return nil, err
+		}
+		key = key[len(key)-len(rest):]
+	}
+	return key, nil
+}
+
+// appendValue appends the encoding of the passed in column value to dst.
+func appendValue(dst art.Key, value interface{}) (art.Key, error) {
+	switch v := value.(type) {
+	case int:
+		return AppendInt64(dst, int64(v)), nil
+	case int8:
+		return AppendInt64(dst, int64(v)), nil
+	case int16:
+		return AppendInt64(dst, int64(v)), nil
+	case int32:
+		return AppendInt64(dst, int64(v)), nil
+	case int64:
+		return AppendInt64(dst, v), nil
+	case uint:
+		return AppendUint64(dst, uint64(v)), nil
+	case uint8:
+		return AppendUint64(dst, uint64(v)), nil
+	case uint16:
+		return AppendUint64(dst, uint64(v)), nil
+	case uint32:
+		return AppendUint64(dst, uint64(v)), nil
+	case uint64:
+		return AppendUint64(dst, v), nil
+	case float32:
+		return AppendFloat64(dst, float64(v)), nil
+	case float64:
+		return AppendFloat64(dst, v), nil
+	case string:
+		return AppendString(dst, v), nil
+	case []byte:
+		return AppendBytes(dst, v), nil
+	case time.Time:
+		return AppendTime(dst, v), nil
+	}
+	return nil, fmt.Errorf("%w: %T", ErrUnsupportedType, value)
+}
+
+// decodeValue decodes the start of the passed in key into the column value pointed to by dst,
+// and returns the rest of the key.
+func decodeValue(key art.Key, dst interface{}) (art.Key, error) {
+	switch p := dst.(type) {
+	case *int, *int8, *int16, *int32, *int64:
+		v, rest, err := DecodeInt64(key)
+		if err != nil {
+			return nil, err
+		}
+		return rest, setInt(p, v)
+	case *uint, *uint8, *uint16, *uint32, *uint64:
+		v, rest, err := DecodeUint64(key)
+		if err != nil {
+			return nil, err
+		}
+		return rest, setUint(p, v)
+	case *float32:
+		v, rest, err := DecodeFloat64(key)
+		*p = float32(v)
+		return rest, err
+	case *float64:
+		v, rest, err := DecodeFloat64(key)
+		*p = v
+		return rest, err
+	case *string:
+		v, rest, err := DecodeString(key)
+		*p = v
+		return rest, err
+	case *[]byte:
+		v, rest, err := DecodeBytes(key)
+		*p = v
+		return rest, err
+	case *time.Time:
+		v, rest, err := DecodeTime(key)
+		*p = v
+		return rest, err
+	}
+	return nil, fmt.Errorf("%w: %T", ErrUnsupportedType, dst)
+}
+
+// setInt stores the passed in number into the signed integer pointed to by dst,
+// failing if it does not fit.
+func setInt(dst interface{}, v int64) error {
+	var ok bool
+	switch p := dst.(type) {
+	case *int:
+		*p, ok = int(v), int64(int(v)) == v
+	case *int8:
+		*p, ok = int8(v), v >= math.MinInt8 && v <= math.MaxInt8
+	case *int16:
+		*p, ok = int16(v), v >= math.MinInt16 && v <= math.MaxInt16
+	case *int32:
+		*p, ok = int32(v), v >= math.MinInt32 && v <= math.MaxInt32
+	case *int64:
+		*p, ok = v, true
+	}
+	if !ok {
+		return fmt.Errorf("%w: %d overflows %T", ErrInvalidKey, v, dst)
+	}
+	return nil
+}
+
+// setUint stores the passed in number into the unsigned integer pointed to by dst,
+// failing if it does not fit.
+func setUint(dst interface{}, v uint64) error {
+	var ok bool
+	switch p := dst.(type) {
+	case *uint:
+		*p, ok = uint(v), uint64(uint(v)) == v
+	case *uint8:
+		*p, ok = uint8(v), v <= math.MaxUint8
+	case *uint16:
+		*p, ok = uint16(v), v <= math.MaxUint16
+	case *uint32:
+		*p, ok = uint32(v), v <= math.MaxUint32
+	case *uint64:
+		*p, ok = v, true
+	}
+	if !ok {
+		return fmt.Errorf("%w: %d overflows %T", ErrInvalidKey, v, dst)
+	}
+	return nil
+}
+
+// invert complements the passed in bytes in place, reversing their order.
+func invert(b []byte) {
+	for i := range b {
+		b[i] = ^b[i]
+	}
+}
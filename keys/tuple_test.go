@@ -0,0 +1,116 @@
+package keys
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"art"
+)
+
+func TestTupleRoundTrip(t *testing.T) {
+	at := time.Date(2024, 5, 1, 8, 0, 0, 42, time.UTC)
+	key, err := EncodeTuple(
+		Asc("tenant\x00a"), Desc(at), Asc(int8(-3)), Desc(uint16(7)), Asc(float32(1.5)), Desc([]byte("x\x00y")),
+	)
+	assert.NoError(t, err)
+
+	var (
+		tenant string
+		ts     time.Time
+		i8     int8
+		u16    uint16
+		f32    float32
+		raw    []byte
+	)
+	rest, err := DecodeTuple(append(key, "tail"...),
+		Asc(&tenant), Desc(&ts), Asc(&i8), Desc(&u16), Asc(&f32), Desc(&raw),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, art.Key("tail"), rest)
+	assert.Equal(t, "tenant\x00a", tenant)
+	assert.True(t, at.Equal(ts))
+	assert.Equal(t, int8(-3), i8)
+	assert.Equal(t, uint16(7), u16)
+	assert.Equal(t, float32(1.5), f32)
+	assert.Equal(t, []byte("x\x00y"), raw)
+
+	// Leading columns can be decoded alone.
+	tenant = ""
+	_, err = DecodeTuple(key, Asc(&tenant))
+	assert.NoError(t, err)
+	assert.Equal(t, "tenant\x00a", tenant)
+}
+
+func TestTupleOrder(t *testing.T) {
+	// Sorted by tenant ascending, then by timestamp descending.
+	rows := []struct {
+		tenant string
+		ts     int64
+	}{
+		{"a", 30}, {"a", 20}, {"a", -10}, {"a\x00", 50}, {"ab", 99}, {"ab", 1}, {"b", math.MaxInt64}, {"b", math.MinInt64},
+	}
+
+	var prev art.Key
+	for i, row := range rows {
+		key, err := EncodeTuple(Asc(row.tenant), Desc(row.ts))
+		assert.NoError(t, err)
+		if i > 0 {
+			assert.Negative(t, bytes.Compare(prev, key), "%+v", row)
+		}
+		prev = key
+	}
+}
+
+func TestTupleLatestEntriesInTree(t *testing.T) {
+	tree := art.NewTyped[int]()
+	for _, tenant := range []string{"a", "ab", "b"} {
+		for ts := 0; ts < 100; ts++ {
+			key, err := EncodeTuple(Asc(tenant), Desc(int64(ts)))
+			assert.NoError(t, err)
+			tree.Insert(key, ts)
+		}
+	}
+
+	prefix, err := EncodeTuple(Asc("a"))
+	assert.NoError(t, err)
+
+	var latest []int
+	for key, ts := range tree.Prefix(prefix) {
+		var tenant string
+		var decoded int64
+		_, err := DecodeTuple(key, Asc(&tenant), Desc(&decoded))
+		assert.NoError(t, err)
+		assert.Equal(t, "a", tenant)
+		assert.Equal(t, int64(ts), decoded)
+
+		latest = append(latest, ts)
+		if len(latest) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, []int{99, 98, 97}, latest)
+}
+
+func TestTupleErrors(t *testing.T) {
+	_, err := EncodeTuple(Asc(struct{}{}))
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+
+	key, err := EncodeTuple(Asc(int64(1000)))
+	assert.NoError(t, err)
+
+	var small int8
+	_, err = DecodeTuple(key, Asc(&small))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	var s string
+	_, err = DecodeTuple(key, Asc(s))
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+
+	var big int64
+	_, err = DecodeTuple(key[:4], Asc(&big))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
@@ -27,115 +27,143 @@ const (
 )
 
 // nullNode represent for nil value, so as not to make redundant allocations.
-var nullNode *artNode = nil
+// It is shared by trees of all value types, see nullRef.
+var nullNode unsafe.Pointer = nil
 
 // node includes metadata of art tree node.
-type node struct {
+type node[V any] struct {
 	size      int
 	prefixLen int
 	prefix    [maxPrefixLen]byte
+	terminal  *artNode[V] // leafNode whose key ends right after the compressed path
+	count     int         // number of leafNodes in the subtree
 }
 
 // node4 is of type Node4
-type node4 struct {
-	node
+type node4[V any] struct {
+	node[V]
 	keys     [node4Max]byte
-	children [node4Max]*artNode
+	children [node4Max]*artNode[V]
 }
 
 // node16 is of type Node16
-type node16 struct {
-	node
+type node16[V any] struct {
+	node[V]
 	keys     [node16Max]byte
-	children [node16Max]*artNode
+	children [node16Max]*artNode[V]
 }
 
 // node48 is of type Node48
-type node48 struct {
-	node
-	keys     [node256Max]byte        // keys[$(prefix_char)] = $(idx in children)
-	children [node48Max + 1]*artNode // Do not use children[0] as 0 is the default value of keys[$(prefix_char)]
+type node48[V any] struct {
+	node[V]
+	keys     [node256Max]byte           // keys[$(prefix_char)] = $(idx in children)
+	children [node48Max + 1]*artNode[V] // Do not use children[0] as 0 is the default value of keys[$(prefix_char)]
 }
 
 // node256 is of type Node256
-type node256 struct {
-	node
-	children [node256Max]*artNode // children[$(char)] = $(child pointer)
+type node256[V any] struct {
+	node[V]
+	children [node256Max]*artNode[V] // children[$(char)] = $(child pointer)
 }
 
 // leafNode contains the real key value data.
-type leafNode struct {
+type leafNode[V any] struct {
 	key   Key
-	value interface{}
+	value V
 }
 
 // artNode is an embedded node type used for art.
-type artNode struct {
+type artNode[V any] struct {
 	nodeType NodeType
+	gen      uint64 // generation of the tree owning the node, only its owner may modify it
 	nodePtr  unsafe.Pointer
 }
 
 // // newLeafNode creates an embedded artNode of leafNode
-func newLeafNode(key []byte, value interface{}) *artNode {
+func newLeafNode[V any](key []byte, value V) *artNode[V] {
 	newKey := make([]byte, len(key))
 	copy(newKey, key)
-	return &artNode{
+	return &artNode[V]{
 		nodeType: LeafNode,
-		nodePtr:  unsafe.Pointer(&leafNode{key: newKey, value: value}),
+		nodePtr:  unsafe.Pointer(&leafNode[V]{key: newKey, value: value}),
 	}
 }
 
 // newNode4 creates an embedded artNode of node4
-func newNode4() *artNode {
-	return &artNode{nodeType: Node4, nodePtr: unsafe.Pointer(&node4{})}
+func newNode4[V any]() *artNode[V] {
+	return &artNode[V]{nodeType: Node4, nodePtr: unsafe.Pointer(&node4[V]{})}
 }
 
 // newNode16 creates an embedded artNode of node16
-func newNode16() *artNode {
-	return &artNode{nodeType: Node16, nodePtr: unsafe.Pointer(&node16{})}
+func newNode16[V any]() *artNode[V] {
+	return &artNode[V]{nodeType: Node16, nodePtr: unsafe.Pointer(&node16[V]{})}
 }
 
 // newNode48 creates an embedded artNode of node48
-func newNode48() *artNode {
-	return &artNode{nodeType: Node48, nodePtr: unsafe.Pointer(&node48{})}
+func newNode48[V any]() *artNode[V] {
+	return &artNode[V]{nodeType: Node48, nodePtr: unsafe.Pointer(&node48[V]{})}
 }
 
 // newNode256 creates an embedded artNode of node256
-func newNode256() *artNode {
-	return &artNode{nodeType: Node256, nodePtr: unsafe.Pointer(&node256{})}
+func newNode256[V any]() *artNode[V] {
+	return &artNode[V]{nodeType: Node256, nodePtr: unsafe.Pointer(&node256[V]{})}
 }
 
 // Key returns the key of the given node, or nil if it is not a leafNode.
-func (n *artNode) Key() Key {
+func (n *artNode[V]) Key() Key {
 	if n.isLeaf() {
 		return n.leafNode().key
 	}
 	return nil
 }
 
-// Value returns the value of the given node, or nil if it is not a leafNode.
-func (n *artNode) Value() interface{} {
+// Value returns the value of the given node, or the zero value if it is not a leafNode.
+func (n *artNode[V]) Value() V {
 	if n.nodeType != LeafNode {
-		return nil
+		var zero V
+		return zero
 	}
 	return n.leafNode().value
 }
 
 // NodeType returns the nodeType of the given node
-func (n *artNode) NodeType() NodeType {
+func (n *artNode[V]) NodeType() NodeType {
 	return n.nodeType
 }
 
+// nullRef returns a reference to nullNode typed for trees storing values of type V.
+func nullRef[V any]() **artNode[V] {
+	return (**artNode[V])(unsafe.Pointer(&nullNode))
+}
+
 // isFull returns whether this particular artNode is full or not .
-func (n *artNode) isFull() bool {
+func (n *artNode[V]) isFull() bool {
 	return n.node().size == n.maxSize()
 }
 
+// leafCount returns the number of leafNodes in the subtree of the current artNode.
+func (n *artNode[V]) leafCount() int {
+	if n.isLeaf() {
+		return 1
+	}
+	return n.node().count
+}
+
+// countLeaves sets the number of leafNodes in the subtree of the current inner artNode
+// from the counts of its children.
+func (n *artNode[V]) countLeaves() {
+	node := n.node()
+	node.count = 0
+	for pos, child := n.nextChild(terminalPos); child != nil; pos, child = n.nextChild(pos + 1) {
+		node.count += child.leafCount()
+	}
+}
+
 // isLeaf returns whether this particular artNode is a leafNode or not .
-func (n *artNode) isLeaf() bool { return n.nodeType == LeafNode }
+func (n *artNode[V]) isLeaf() bool { return n.nodeType == LeafNode }
 
 // isMatch returns whether the key stored in the leafNode matches the passed in key or not .
-func (n *artNode) isMatch(key []byte) bool {
+func (n *artNode[V]) isMatch(key []byte) bool {
 	if n.nodeType != LeafNode {
 		return false
 	}
@@ -147,17 +175,11 @@ func (n *artNode) isMatch(key []byte) bool {
 
 // prefixMismatch returns the position of first byte that differ between the passed in key
 // and the compressed path of the current node at the specified depth.
-func (n *artNode) prefixMismatch(key []byte, depth int) int {
+func (n *artNode[V]) prefixMismatch(key []byte, depth int) int {
 	var idx int
 
-	var keyChar byte
 	for idx = 0; idx < min(maxPrefixLen, n.node().prefixLen); idx++ {
-		if depth+idx < 0 || depth+idx >= len(key) {
-			keyChar = byte(0)
-		} else {
-			keyChar = key[depth+idx]
-		}
-		if keyChar != n.node().prefix[idx] {
+		if depth+idx >= len(key) || key[depth+idx] != n.node().prefix[idx] {
 			return idx
 		}
 	}
@@ -165,7 +187,7 @@ func (n *artNode) prefixMismatch(key []byte, depth int) int {
 	if n.node().prefixLen > maxPrefixLen {
 		minKey := n.minimum().leafNode().key
 		for ; idx < n.node().prefixLen; idx++ {
-			if key[depth+idx] != minKey[depth+idx] {
+			if depth+idx >= len(key) || key[depth+idx] != minKey[depth+idx] {
 				return idx
 			}
 		}
@@ -176,12 +198,14 @@ func (n *artNode) prefixMismatch(key []byte, depth int) int {
 
 // index returns the position of the given key byte's child pointer in the children array.
 // If not found, return -1.
-func (n *artNode) index(key byte) int {
+func (n *artNode[V]) index(key byte) int {
 	switch n.nodeType {
 	case Node4:
-		return bytes.IndexByte(n.node4().keys[:], key)
+		n4 := n.node4()
+		return bytes.IndexByte(n4.keys[:n4.size], key)
 	case Node16:
-		return bytes.IndexByte(n.node16().keys[:], key)
+		n16 := n.node16()
+		return bytes.IndexByte(n16.keys[:n16.size], key)
 	case Node48:
 		return int(n.node48().keys[key])
 	case Node256:
@@ -192,9 +216,9 @@ func (n *artNode) index(key byte) int {
 
 // findChild returns a pointer to the child that matches the passed in key,
 // or nil if not present.
-func (n *artNode) findChild(key byte) **artNode {
+func (n *artNode[V]) findChild(key byte) **artNode[V] {
 	if n == nil {
-		return &nullNode
+		return nullRef[V]()
 	}
 
 	var idx int
@@ -206,7 +230,7 @@ func (n *artNode) findChild(key byte) **artNode {
 	}
 	// Not found.
 	if idx < 0 {
-		return &nullNode
+		return nullRef[V]()
 	}
 
 	switch n.nodeType {
@@ -216,22 +240,22 @@ func (n *artNode) findChild(key byte) **artNode {
 		return &n.node16().children[idx]
 	case Node48:
 		if idx == 0 { // children[0] is not used in Node48
-			return &nullNode
+			return nullRef[V]()
 		}
 		return &n.node48().children[idx]
 	case Node256:
 		if n.node256().children[idx] == nil {
-			return &nullNode
+			return nullRef[V]()
 		}
 		return &n.node256().children[idx]
 	}
 
-	return &nullNode
+	return nullRef[V]()
 }
 
 // addChild adds the passed in artNode to the current artNode's children at the specified key.
 // The current node will grow if necessary when the insertion to take place.
-func (n *artNode) addChild(key byte, node *artNode) {
+func (n *artNode[V]) addChild(key byte, node *artNode[V]) {
 	switch n.nodeType {
 	case Node4:
 		if n.isFull() {
@@ -302,9 +326,20 @@ func (n *artNode) addChild(key byte, node *artNode) {
 	}
 }
 
+// addLeaf adds the passed in leafNode to the current artNode, as the terminal leafNode
+// if its key ends at the specified depth, or as the child at its key byte otherwise.
+func (n *artNode[V]) addLeaf(leaf *artNode[V], depth int) {
+	key := leaf.leafNode().key
+	if depth == len(key) {
+		n.node().terminal = leaf
+		return
+	}
+	n.addChild(key[depth], leaf)
+}
+
 // RemoveChild removes the child of the passed in key,
 // and will shrink if it falls below its minimum size.
-func (n *artNode) RemoveChild(key byte) {
+func (n *artNode[V]) RemoveChild(key byte) {
 	switch n.nodeType {
 	case Node4:
 		n4 := n.node4()
@@ -350,16 +385,35 @@ func (n *artNode) RemoveChild(key byte) {
 		n256.children[n.index(key)] = nil
 		n256.size--
 	}
-	if n.node().size < n.minSize() {
+	if n.isUnderfull() {
+		n.shrink()
+	}
+}
+
+// removeTerminal removes the terminal leafNode,
+// and will shrink if the current artNode falls below its minimum size.
+func (n *artNode[V]) removeTerminal() {
+	n.node().terminal = nil
+	if n.isUnderfull() {
 		n.shrink()
 	}
 }
 
+// isUnderfull returns whether the current artNode has fewer children than its type requires.
+// The terminal leafNode counts as a child of a Node4, so that it may hold a single other child.
+func (n *artNode[V]) isUnderfull() bool {
+	size := n.node().size
+	if n.nodeType == Node4 && n.node().terminal != nil {
+		size++
+	}
+	return size < n.minSize()
+}
+
 // grow upgrades the current artNode to contain more children.
-func (n *artNode) grow() {
+func (n *artNode[V]) grow() {
 	switch n.nodeType {
 	case Node4:
-		newNode := newNode16()
+		newNode := newNode16[V]()
 		newNode.copyMeta(n)
 		newNode16 := newNode.node16()
 		n4 := n.node4()
@@ -369,7 +423,7 @@ func (n *artNode) grow() {
 		}
 		n.replaceWith(newNode)
 	case Node16:
-		newNode := newNode48()
+		newNode := newNode48[V]()
 		newNode.copyMeta(n)
 		newNode48 := newNode.node48()
 		n16 := n.node16()
@@ -379,7 +433,7 @@ func (n *artNode) grow() {
 		}
 		n.replaceWith(newNode)
 	case Node48:
-		newNode := newNode256()
+		newNode := newNode256[V]()
 		newNode.copyMeta(n)
 		newNode256 := newNode.node256()
 		n48 := n.node48()
@@ -387,7 +441,7 @@ func (n *artNode) grow() {
 			if n48.keys[i] == byte(0) {
 				continue
 			}
-			if n48.children[n48.keys[i]] != nil && n48.children[n48.keys[i]] != nullNode {
+			if n48.children[n48.keys[i]] != nil {
 				newNode256.children[byte(i)] = n48.children[n48.keys[i]]
 			}
 		}
@@ -398,11 +452,18 @@ func (n *artNode) grow() {
 }
 
 // shrink downgrades the current artNode to reduce the memory cost.
-func (n *artNode) shrink() {
+func (n *artNode[V]) shrink() {
 	switch n.nodeType {
 	case Node4:
+		// Only one of the terminal leafNode and a single child is left.
 		n4 := n.node4()
 		newNode := n4.children[0]
+		if n4.size == 0 {
+			newNode = n4.terminal
+		}
+		if newNode.gen != n.gen {
+			newNode = newNode.clone(n.gen)
+		}
 		if !newNode.isLeaf() {
 			currentPrefixLen := n4.prefixLen
 			if currentPrefixLen < maxPrefixLen {
@@ -420,7 +481,7 @@ func (n *artNode) shrink() {
 		n.replaceWith(newNode)
 	case Node16:
 		n16 := n.node16()
-		newNode := newNode4()
+		newNode := newNode4[V]()
 		newNode.copyMeta(n)
 		newNode4 := newNode.node4()
 		newNode4.size = 0
@@ -432,7 +493,7 @@ func (n *artNode) shrink() {
 		n.replaceWith(newNode)
 	case Node48:
 		n48 := n.node48()
-		newNode := newNode16()
+		newNode := newNode16[V]()
 		newNode.copyMeta(n)
 		newNode16 := newNode.node16()
 		newNode16.size = 0
@@ -448,7 +509,7 @@ func (n *artNode) shrink() {
 		n.replaceWith(newNode)
 	case Node256:
 		n256 := n.node256()
-		newNode := newNode48()
+		newNode := newNode48[V]()
 		newNode.copyMeta(n)
 		newNode48 := newNode.node48()
 		newNode48.size = 0
@@ -467,7 +528,7 @@ func (n *artNode) shrink() {
 // longestCommonPrefix returns the longest number of bytes
 // that match between the current artNode's prefix
 // and the passed in artNode at the specified depth.
-func (n *artNode) longestCommonPrefix(other *artNode, depth int) int {
+func (n *artNode[V]) longestCommonPrefix(other *artNode[V], depth int) int {
 	limit := min(len(n.leafNode().key), len(other.leafNode().key)) - depth
 	for i := 0; i < limit; i++ {
 		if n.leafNode().key[depth+i] != other.leafNode().key[depth+i] {
@@ -478,7 +539,7 @@ func (n *artNode) longestCommonPrefix(other *artNode, depth int) int {
 }
 
 // minSize returns the minimum number of children for the current artNode.
-func (n *artNode) minSize() int {
+func (n *artNode[V]) minSize() int {
 	switch n.nodeType {
 	case Node4:
 		return node4Min
@@ -493,7 +554,7 @@ func (n *artNode) minSize() int {
 }
 
 // maxSize returns the maximum number of children for the current artNode.
-func (n *artNode) maxSize() int {
+func (n *artNode[V]) maxSize() int {
 	switch n.nodeType {
 	case Node4:
 		return node4Max
@@ -508,14 +569,20 @@ func (n *artNode) maxSize() int {
 }
 
 // minimum returns the minimum child at the current artNode.
-func (n *artNode) minimum() *artNode {
+func (n *artNode[V]) minimum() *artNode[V] {
 	if n == nil {
 		return nil
 	}
 
-	switch n.nodeType {
-	case LeafNode:
+	if n.isLeaf() {
 		return n
+	}
+	// The terminal leafNode is a prefix of all the other keys below.
+	if terminal := n.node().terminal; terminal != nil {
+		return terminal
+	}
+
+	switch n.nodeType {
 	case Node4:
 		return n.node4().children[0].minimum()
 	case Node16:
@@ -538,7 +605,7 @@ func (n *artNode) minimum() *artNode {
 }
 
 //maximum returns the maximum child at the current artNode.
-func (n *artNode) maximum() *artNode {
+func (n *artNode[V]) maximum() *artNode[V] {
 	if n == nil {
 		return nil
 	}
@@ -571,43 +638,70 @@ func (n *artNode) maximum() *artNode {
 }
 
 // node returns the metadata node of the current artNode.
-func (n *artNode) node() *node {
-	return (*node)(n.nodePtr)
+func (n *artNode[V]) node() *node[V] {
+	return (*node[V])(n.nodePtr)
 }
 
 // node4 returns the metadata node4 of the current artNode.
-func (n *artNode) node4() *node4 {
-	return (*node4)(n.nodePtr)
+func (n *artNode[V]) node4() *node4[V] {
+	return (*node4[V])(n.nodePtr)
 }
 
 // node16 returns the metadata node16 of the current artNode.
-func (n *artNode) node16() *node16 {
-	return (*node16)(n.nodePtr)
+func (n *artNode[V]) node16() *node16[V] {
+	return (*node16[V])(n.nodePtr)
 }
 
 // node48 returns the metadata node48 of the current artNode.
-func (n *artNode) node48() *node48 {
-	return (*node48)(n.nodePtr)
+func (n *artNode[V]) node48() *node48[V] {
+	return (*node48[V])(n.nodePtr)
 }
 
 // node256 returns the metadata node256 of the current artNode.
-func (n *artNode) node256() *node256 {
-	return (*node256)(n.nodePtr)
+func (n *artNode[V]) node256() *node256[V] {
+	return (*node256[V])(n.nodePtr)
 }
 
 // leafNode returns the metadata leafNode of the current artNode.
-func (n *artNode) leafNode() *leafNode {
-	return (*leafNode)(n.nodePtr)
+func (n *artNode[V]) leafNode() *leafNode[V] {
+	return (*leafNode[V])(n.nodePtr)
 }
 
-// replaceWith replaces the current artNode with the passed in artNode.
-func (n *artNode) replaceWith(other *artNode) {
+// replaceWith replaces the current artNode with the passed in artNode,
+// which must not be shared with another tree. The generation of the current artNode is kept.
+func (n *artNode[V]) replaceWith(other *artNode[V]) {
+	gen := n.gen
 	*n = *other
+	n.gen = gen
+}
+
+// clone returns a copy of the current artNode owned by the passed in generation.
+// Children are shared with the current artNode.
+func (n *artNode[V]) clone(gen uint64) *artNode[V] {
+	c := &artNode[V]{nodeType: n.nodeType, gen: gen}
+	switch n.nodeType {
+	case LeafNode:
+		l := *n.leafNode()
+		c.nodePtr = unsafe.Pointer(&l)
+	case Node4:
+		n4 := *n.node4()
+		c.nodePtr = unsafe.Pointer(&n4)
+	case Node16:
+		n16 := *n.node16()
+		c.nodePtr = unsafe.Pointer(&n16)
+	case Node48:
+		n48 := *n.node48()
+		c.nodePtr = unsafe.Pointer(&n48)
+	case Node256:
+		n256 := *n.node256()
+		c.nodePtr = unsafe.Pointer(&n256)
+	}
+	return c
 }
 
-// copyMeta copies the prefix and size metadata from the passed in artNode
+// copyMeta copies the prefix, size, terminal and count metadata from the passed in artNode
 // to the current artNode.
-func (n *artNode) copyMeta(src *artNode) {
+func (n *artNode[V]) copyMeta(src *artNode[V]) {
 	if src == nil {
 		return
 	}
@@ -615,6 +709,8 @@ func (n *artNode) copyMeta(src *artNode) {
 	from := src.node()
 	to.size = from.size
 	to.prefixLen = from.prefixLen
+	to.terminal = from.terminal
+	to.count = from.count
 
 	for i, limit := 0, min(from.prefixLen, maxPrefixLen); i < limit; i++ {
 		to.prefix[i] = from.prefix[i]
@@ -628,3 +724,152 @@ func min(a int, b int) int {
 	}
 	return b
 }
+
+// comparePrefix compares the compressed path of the current node against the
+// passed in key at the specified depth. It returns 0 if the whole path matches,
+// a negative number if the path sorts before the key and a positive number
+// if it sorts after it (including when the key ends inside the path).
+func (n *artNode[V]) comparePrefix(key []byte, depth int) int {
+	node := n.node()
+	var minKey []byte
+	for i := 0; i < node.prefixLen; i++ {
+		var p byte
+		if i < maxPrefixLen {
+			p = node.prefix[i]
+		} else {
+			if minKey == nil {
+				minKey = n.minimum().leafNode().key
+			}
+			p = minKey[depth+i]
+		}
+		if depth+i >= len(key) {
+			return 1
+		}
+		if p != key[depth+i] {
+			if p < key[depth+i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// childPos returns the position from which a search for the first child
+// with a key byte greater than or equal to the passed in key should start.
+func (n *artNode[V]) childPos(key byte) int {
+	switch n.nodeType {
+	case Node4:
+		n4 := n.node4()
+		for i := 0; i < n4.size; i++ {
+			if n4.keys[i] >= key {
+				return i
+			}
+		}
+		return n4.size
+	case Node16:
+		n16 := n.node16()
+		return sort.Search(n16.size, func(i int) bool {
+			return n16.keys[i] >= key
+		})
+	}
+	return int(key)
+}
+
+// keyAt returns the key byte of the child stored at the passed in position.
+func (n *artNode[V]) keyAt(pos int) byte {
+	switch n.nodeType {
+	case Node4:
+		return n.node4().keys[pos]
+	case Node16:
+		return n.node16().keys[pos]
+	}
+	return byte(pos)
+}
+
+// terminalPos is the position of the terminal leafNode, which comes before all the other children.
+const terminalPos = -1
+
+// nextChild returns the first child at or after the passed in position in key order,
+// together with its position. The child is nil if there is no such child.
+// Positions are indexes into the children array for Node4 and Node16,
+// and key bytes for Node48 and Node256. The terminal leafNode is at terminalPos.
+func (n *artNode[V]) nextChild(pos int) (int, *artNode[V]) {
+	if pos <= terminalPos {
+		if terminal := n.node().terminal; terminal != nil {
+			return terminalPos, terminal
+		}
+		pos = 0
+	}
+	switch n.nodeType {
+	case Node4:
+		n4 := n.node4()
+		if pos < n4.size {
+			return pos, n4.children[pos]
+		}
+	case Node16:
+		n16 := n.node16()
+		if pos < n16.size {
+			return pos, n16.children[pos]
+		}
+	case Node48:
+		n48 := n.node48()
+		for ; pos < node256Max; pos++ {
+			if idx := n48.keys[pos]; idx > 0 {
+				return pos, n48.children[idx]
+			}
+		}
+	case Node256:
+		n256 := n.node256()
+		for ; pos < node256Max; pos++ {
+			if n256.children[pos] != nil {
+				return pos, n256.children[pos]
+			}
+		}
+	}
+	return -1, nil
+}
+
+// prevChild returns the last child at or before the passed in position in key order,
+// together with its position. The child is nil if there is no such child.
+func (n *artNode[V]) prevChild(pos int) (int, *artNode[V]) {
+	if pos >= node256Max {
+		pos = node256Max - 1
+	}
+	switch n.nodeType {
+	case Node4:
+		n4 := n.node4()
+		if pos >= n4.size {
+			pos = n4.size - 1
+		}
+		if pos >= 0 {
+			return pos, n4.children[pos]
+		}
+	case Node16:
+		n16 := n.node16()
+		if pos >= n16.size {
+			pos = n16.size - 1
+		}
+		if pos >= 0 {
+			return pos, n16.children[pos]
+		}
+	case Node48:
+		n48 := n.node48()
+		for ; pos >= 0; pos-- {
+			if idx := n48.keys[pos]; idx > 0 {
+				return pos, n48.children[idx]
+			}
+		}
+	case Node256:
+		n256 := n.node256()
+		for ; pos >= 0; pos-- {
+			if n256.children[pos] != nil {
+				return pos, n256.children[pos]
+			}
+		}
+	}
+	if terminal := n.node().terminal; terminal != nil && pos >= terminalPos {
+		return terminalPos, terminal
+	}
+	return -1, nil
+}
@@ -8,7 +8,7 @@ import (
 )
 
 func TestLeafValue(t *testing.T) {
-	leafNode := newLeafNode([]byte("foo"), "foo")
+	leafNode := newLeafNode[Value]([]byte("foo"), "foo")
 
 	if leafNode.Value() != "foo" {
 		t.Error("Unexpected value for leafNode node")
@@ -17,13 +17,13 @@ func TestLeafValue(t *testing.T) {
 }
 
 func TestNodeAddChild(t *testing.T) {
-	nodes := []*artNode{newNode4(), newNode16(), newNode48(), newNode256()}
+	nodes := []*artNode[Value]{newNode4[Value](), newNode16[Value](), newNode48[Value](), newNode256[Value]()}
 
 	for node := range nodes {
 		n := nodes[node]
 
 		for i := 0; i < n.maxSize(); i++ {
-			newChild := newLeafNode([]byte{byte(i)}, byte(i))
+			newChild := newLeafNode[Value]([]byte{byte(i)}, byte(i))
 			n.addChild(byte(i), newChild)
 		}
 
@@ -39,13 +39,13 @@ func TestNodeAddChild(t *testing.T) {
 }
 
 func TestIndexForAllNodeTypes(t *testing.T) {
-	nodes := []*artNode{newNode4(), newNode16(), newNode48(), newNode256()}
+	nodes := []*artNode[Value]{newNode4[Value](), newNode16[Value](), newNode48[Value](), newNode256[Value]()}
 
 	for node := range nodes {
 		n := nodes[node]
 
 		for i := 0; i < n.maxSize(); i++ {
-			newChild := newLeafNode([]byte{byte(i)}, byte(i))
+			newChild := newLeafNode[Value]([]byte{byte(i)}, byte(i))
 			n.addChild(byte(i), newChild)
 		}
 
@@ -65,8 +65,8 @@ func TestIndexForAllNodeTypes(t *testing.T) {
 }
 
 func TestArtNode4AddChild1AndFindChild(t *testing.T) {
-	n := newNode4()
-	n2 := newNode4()
+	n := newNode4[Value]()
+	n2 := newNode4[Value]()
 	n.addChild('a', n2)
 
 	assert.Equal(t, 1, n.node().size)
@@ -76,9 +76,9 @@ func TestArtNode4AddChild1AndFindChild(t *testing.T) {
 }
 
 func TestArtNode4AddChildTwicePreserveSorted(t *testing.T) {
-	n := newNode4()
-	n2 := newNode4()
-	n3 := newNode4()
+	n := newNode4[Value]()
+	n2 := newNode4[Value]()
+	n3 := newNode4[Value]()
 	n.addChild('b', n2)
 	n.addChild('a', n3)
 
@@ -96,10 +96,10 @@ func TestArtNode4AddChildTwicePreserveSorted(t *testing.T) {
 }
 
 func TestArtNode4AddChild4PreserveSorted(t *testing.T) {
-	n := newNode4()
+	n := newNode4[Value]()
 
 	for i := 4; i > 0; i-- {
-		n.addChild(byte(i), newNode4())
+		n.addChild(byte(i), newNode4[Value]())
 	}
 
 	if n.node4().size < 4 {
@@ -113,7 +113,7 @@ func TestArtNode4AddChild4PreserveSorted(t *testing.T) {
 }
 
 func TestGrow(t *testing.T) {
-	nodes := []*artNode{newNode4(), newNode16(), newNode48()}
+	nodes := []*artNode[Value]{newNode4[Value](), newNode16[Value](), newNode48[Value]()}
 	expectedTypes := []NodeType{Node16, Node48, Node256}
 
 	for i := range nodes {
@@ -127,7 +127,7 @@ func TestGrow(t *testing.T) {
 }
 
 func TestShrink(t *testing.T) {
-	nodes := []*artNode{newNode48()}
+	nodes := []*artNode[Value]{newNode48[Value]()}
 	expectedTypes := []NodeType{Node16}
 
 	for i := range nodes {
@@ -135,9 +135,9 @@ func TestShrink(t *testing.T) {
 
 		for j := 0; j < node.minSize(); j++ {
 			if node.nodeType != Node4 {
-				node.addChild(byte(i), newNode4())
+				node.addChild(byte(i), newNode4[Value]())
 			} else {
-				node.addChild(byte(i), newLeafNode(nil, nil))
+				node.addChild(byte(i), newLeafNode[Value](nil, nil))
 			}
 		}
 
@@ -151,7 +151,7 @@ func TestShrink(t *testing.T) {
 func TestNewLeafNode(t *testing.T) {
 	key := []byte{'a', 'r', 't'}
 	value := "tree"
-	l := newLeafNode(key, value)
+	l := newLeafNode[Value](key, value)
 
 	if &l.leafNode().key == &key {
 		t.Errorf("Address of key byte slices should not match.")
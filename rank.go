@@ -0,0 +1,99 @@
+package art
+
+import "bytes"
+
+// Rank returns the number of keys in the tree that are less than the passed in key.
+// It sums the leaf counts of the subtrees on the left of the search path.
+func (t *tree[V]) Rank(key Key) int {
+	rank, _ := t.rankHelper(key)
+	return rank
+}
+
+// CountRange returns the number of keys that lie between lo and hi,
+// with the bounds interpreted as in Range, without visiting them.
+func (t *tree[V]) CountRange(lo, hi Key, opts RangeOptions) int {
+	upper := int(t.size)
+	if opts.Hi != Unbounded {
+		rank, found := t.rankHelper(hi)
+		upper = rank
+		if found && opts.Hi == Inclusive {
+			upper++
+		}
+	}
+
+	lower := 0
+	if opts.Lo != Unbounded {
+		rank, found := t.rankHelper(lo)
+		lower = rank
+		if found && opts.Lo == Exclusive {
+			lower++
+		}
+	}
+
+	if upper < lower {
+		return 0
+	}
+	return upper - lower
+}
+
+// rankHelper returns the number of keys less than the passed in key,
+// and whether the key itself is present in the tree.
+func (t *tree[V]) rankHelper(key Key) (int, bool) {
+	rank := 0
+	current := t.root
+	depth := 0
+	for current != nil {
+		if current.isLeaf() {
+			cmp := bytes.Compare(current.leafNode().key, key)
+			if cmp < 0 {
+				rank++
+			}
+			return rank, cmp == 0
+		}
+
+		switch cmp := current.comparePrefix(key, depth); {
+		case cmp < 0:
+			return rank + current.node().count, false
+		case cmp > 0:
+			return rank, false
+		}
+		depth += current.node().prefixLen
+
+		if depth == len(key) {
+			return rank, current.node().terminal != nil
+		}
+		if current.node().terminal != nil {
+			rank++
+		}
+		for pos, child := current.nextChild(0); child != nil && current.keyAt(pos) < key[depth]; pos, child = current.nextChild(pos + 1) {
+			rank += child.leafCount()
+		}
+		current = *(current.findChild(key[depth]))
+		depth++
+	}
+
+	return rank, false
+}
+
+// Select returns the key at the passed in position in lexicographical order, counting from 0,
+// together with its value.
+func (t *tree[V]) Select(i int) (Key, V, bool) {
+	if i < 0 || i >= int(t.size) {
+		return leafEntry[V](nil)
+	}
+
+	current := t.root
+	for current != nil && !current.isLeaf() {
+		var next *artNode[V]
+		for pos, child := current.nextChild(terminalPos); child != nil; pos, child = current.nextChild(pos + 1) {
+			count := child.leafCount()
+			if i < count {
+				next = child
+				break
+			}
+			i -= count
+		}
+		current = next
+	}
+	return leafEntry(current)
+}
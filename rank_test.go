@@ -0,0 +1,150 @@
+package art
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"art/testdata"
+)
+
+func TestRankSelectEmptyTree(t *testing.T) {
+	tree := newArt[Value]()
+
+	assert.Zero(t, tree.Rank(Key("a")))
+	_, _, ok := tree.Select(0)
+	assert.False(t, ok)
+}
+
+func TestRankSelectKeysWithPrefixes(t *testing.T) {
+	keys := []string{"", "a", "ab", "ab\x00", "abc", "averyveryverylongprefix/1", "averyveryverylongprefix/2", "b"}
+	tree := newArt[int]()
+	for i, key := range keys {
+		tree.Insert(Key(key), i)
+	}
+
+	for i, key := range keys {
+		assert.Equal(t, i, tree.Rank(Key(key)), "%q", key)
+
+		k, v, ok := tree.Select(i)
+		assert.True(t, ok)
+		assert.Equal(t, Key(key), k)
+		assert.Equal(t, i, v)
+	}
+
+	assert.Equal(t, 5, tree.Rank(Key("averyvery")))
+	assert.Equal(t, 7, tree.Rank(Key("averyveryverylongprefix/3")))
+	assert.Equal(t, 7, tree.Rank(Key("az")))
+	assert.Equal(t, 8, tree.Rank(Key("c")))
+
+	_, _, ok := tree.Select(len(keys))
+	assert.False(t, ok)
+	_, _, ok = tree.Select(-1)
+	assert.False(t, ok)
+}
+
+func TestRankSelectManyWords(t *testing.T) {
+	tree := newArt[Value]()
+	words := testdata.LoadTestFile("testdata/data/words.txt")
+	for i, w := range words {
+		tree.Insert(w, w)
+		// Remove every third word to exercise the counts on deletion.
+		if i%3 == 0 {
+			tree.Delete(w)
+		}
+	}
+	assert.NoError(t, tree.Validate())
+
+	var sorted [][]byte
+	for i, w := range words {
+		if i%3 != 0 {
+			sorted = append(sorted, w)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i], sorted[j]) < 0
+	})
+	assert.Equal(t, len(sorted), tree.Size())
+
+	for i := 0; i < len(sorted); i += 7 {
+		assert.Equal(t, i, tree.Rank(sorted[i]))
+
+		key, _, ok := tree.Select(i)
+		assert.True(t, ok)
+		assert.Equal(t, sorted[i], key)
+	}
+
+	for i := 0; i < len(words); i += 11 {
+		query := append(append(Key{}, words[i]...), '~')
+		expected := sort.Search(len(sorted), func(j int) bool {
+			return bytes.Compare(sorted[j], query) >= 0
+		})
+		assert.Equal(t, expected, tree.Rank(query), "%q", query)
+	}
+}
+
+func TestRankSelectSnapshot(t *testing.T) {
+	tree := newArt[int]()
+	for i := 0; i < 1000; i++ {
+		tree.Insert(Key{byte(i >> 8), byte(i)}, i)
+	}
+	snapshot := tree.Snapshot()
+
+	for i := 0; i < 1000; i += 2 {
+		tree.Delete(Key{byte(i >> 8), byte(i)})
+	}
+	assert.NoError(t, tree.Validate())
+	assert.NoError(t, snapshot.Validate())
+
+	_, value, _ := tree.Select(10)
+	assert.Equal(t, 21, value)
+	_, value, _ = snapshot.Select(10)
+	assert.Equal(t, 10, value)
+	assert.Equal(t, 250, tree.Rank(Key{1, 245}))
+	assert.Equal(t, 501, snapshot.Rank(Key{1, 245}))
+}
+
+func TestCountRangeMatchesRange(t *testing.T) {
+	tree := newArt[Value]()
+	words := testdata.LoadTestFile("testdata/data/words.txt")
+	for i := 0; i < len(words); i += 10 {
+		tree.Insert(words[i], words[i])
+	}
+
+	bounds := []Key{nil, Key(""), Key("a"), Key("ab"), Key("abandon"), Key("m"), Key("mz"), Key("zz"), words[1000], words[50000]}
+	kinds := []Bound{Inclusive, Exclusive, Unbounded}
+	for _, lo := range bounds {
+		for _, hi := range bounds {
+			for _, loKind := range kinds {
+				for _, hiKind := range kinds {
+					opts := RangeOptions{Lo: loKind, Hi: hiKind}
+					expected := 0
+					tree.Range(lo, hi, opts, func(Node) {
+						expected++
+					})
+					assert.Equal(t, expected, tree.CountRange(lo, hi, opts), "%q %q %+v", lo, hi, opts)
+				}
+			}
+		}
+	}
+}
+
+func TestCountPrefixAfterDeletes(t *testing.T) {
+	tree := newArt[Value]()
+	for _, key := range []string{"tenant/a/1", "tenant/a/2", "tenant/a/3", "tenant/b/1", "tenant/ab"} {
+		tree.Insert(Key(key), key)
+	}
+
+	assert.Equal(t, 5, tree.CountPrefix(Key("tenant/")))
+	assert.Equal(t, 4, tree.CountPrefix(Key("tenant/a")))
+	assert.Equal(t, 3, tree.CountPrefix(Key("tenant/a/")))
+	assert.Equal(t, 1, tree.CountPrefix(Key("tenant/a/2")))
+	assert.Equal(t, 0, tree.CountPrefix(Key("tenant/c")))
+
+	tree.Delete(Key("tenant/a/2"))
+	assert.Equal(t, 2, tree.CountPrefix(Key("tenant/a/")))
+	assert.Equal(t, 4, tree.CountPrefix(Key("tenant")))
+	assert.Equal(t, 2, tree.CountRange(Key("tenant/a/"), Key("tenant/a/~"), RangeOptions{}))
+}
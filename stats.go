@@ -0,0 +1,77 @@
+package art
+
+import "unsafe"
+
+// Stats - statistics about the structure and the memory footprint of a tree.
+type Stats struct {
+	Nodes        map[NodeType]int // number of nodes of each type, including leaves
+	Leaves       int              // number of leafNodes
+	MaxDepth     int              // largest number of inner nodes above a leafNode
+	AvgDepth     float64          // average number of inner nodes above a leafNode
+	PrefixLens   map[int]int      // number of inner nodes by compressed path length
+	LongPrefixes int              // number of inner nodes whose compressed path exceeds maxPrefixLen
+	Bytes        map[NodeType]int // estimated memory of the nodes of each type, leaf keys included
+}
+
+// Stats returns statistics about the structure and the memory footprint of the tree.
+func (t *tree[V]) Stats() Stats {
+	stats := Stats{
+		Nodes:      make(map[NodeType]int),
+		PrefixLens: make(map[int]int),
+		Bytes:      make(map[NodeType]int),
+	}
+
+	var totalDepth int
+	t.statsHelper(t.root, 0, &stats, &totalDepth)
+	if stats.Leaves > 0 {
+		stats.AvgDepth = float64(totalDepth) / float64(stats.Leaves)
+	}
+	return stats
+}
+
+// statsHelper is a helper function of Stats.
+func (t *tree[V]) statsHelper(current *artNode[V], depth int, stats *Stats, totalDepth *int) {
+	if current == nil {
+		return
+	}
+
+	stats.Nodes[current.nodeType]++
+	stats.Bytes[current.nodeType] += current.footprint()
+
+	if current.isLeaf() {
+		stats.Leaves++
+		*totalDepth += depth
+		if depth > stats.MaxDepth {
+			stats.MaxDepth = depth
+		}
+		return
+	}
+
+	prefixLen := current.node().prefixLen
+	stats.PrefixLens[prefixLen]++
+	if prefixLen > maxPrefixLen {
+		stats.LongPrefixes++
+	}
+	for pos, child := current.nextChild(terminalPos); child != nil; pos, child = current.nextChild(pos + 1) {
+		t.statsHelper(child, depth+1, stats, totalDepth)
+	}
+}
+
+// footprint returns the estimated number of bytes used by the current artNode,
+// not counting its children.
+func (n *artNode[V]) footprint() int {
+	size := int(unsafe.Sizeof(*n))
+	switch n.nodeType {
+	case LeafNode:
+		size += int(unsafe.Sizeof(*n.leafNode())) + cap(n.leafNode().key)
+	case Node4:
+		size += int(unsafe.Sizeof(*n.node4()))
+	case Node16:
+		size += int(unsafe.Sizeof(*n.node16()))
+	case Node48:
+		size += int(unsafe.Sizeof(*n.node48()))
+	case Node256:
+		size += int(unsafe.Sizeof(*n.node256()))
+	}
+	return size
+}
@@ -0,0 +1,60 @@
+package art
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"art/testdata"
+)
+
+func TestStatsEmptyTree(t *testing.T) {
+	stats := newArt[Value]().Stats()
+
+	assert.Zero(t, stats.Leaves)
+	assert.Zero(t, stats.MaxDepth)
+	assert.Zero(t, stats.AvgDepth)
+	assert.Empty(t, stats.Nodes)
+}
+
+func TestStatsPrefixes(t *testing.T) {
+	tree := newArt[Value]()
+	tree.Insert(Key("averyveryverylongprefix/1"), 1)
+	tree.Insert(Key("averyveryverylongprefix/2"), 2)
+	tree.Insert(Key("b"), 3)
+
+	stats := tree.Stats()
+
+	assert.Equal(t, map[NodeType]int{LeafNode: 3, Node4: 2}, stats.Nodes)
+	assert.Equal(t, 3, stats.Leaves)
+	assert.Equal(t, 2, stats.MaxDepth)
+	assert.InDelta(t, 5.0/3, stats.AvgDepth, 1e-9)
+	assert.Equal(t, map[int]int{0: 1, 23: 1}, stats.PrefixLens)
+	assert.Equal(t, 1, stats.LongPrefixes)
+	assert.Greater(t, stats.Bytes[LeafNode], 3*len("averyveryverylongprefix/1"))
+}
+
+func TestStatsManyWords(t *testing.T) {
+	tree := newArt[Value]()
+
+	words := testdata.LoadTestFile("testdata/data/words.txt")
+	for _, w := range words {
+		tree.Insert(w, w)
+	}
+
+	stats := tree.Stats()
+
+	assert.Equal(t, map[NodeType]int{LeafNode: 235886, Node4: 113419, Node16: 10433, Node48: 403, Node256: 1}, stats.Nodes)
+	assert.Equal(t, 235886, stats.Leaves)
+	assert.Greater(t, stats.MaxDepth, 1)
+	assert.Greater(t, stats.AvgDepth, 1.0)
+
+	inner := 0
+	for _, count := range stats.PrefixLens {
+		inner += count
+	}
+	assert.Equal(t, 113419+10433+403+1, inner)
+	for nodeType, count := range stats.Nodes {
+		assert.Greater(t, stats.Bytes[nodeType], count)
+	}
+}
@@ -1,27 +1,84 @@
 package art
 
+import (
+	"bytes"
+	"sync/atomic"
+)
+
 // tree - adaptive radix tree type.
-type tree struct {
-	root *artNode
+type tree[V any] struct {
+	root *artNode[V]
 	size int64
+	gen  uint64 // generation of the nodes the tree may modify in place
 }
 
+// lastGen is the last generation handed out to a tree.
+var lastGen uint64
+
 // newArt returns art with 0 nodes.
-func newArt() *tree {
-	return &tree{root: nil, size: 0}
+func newArt[V any]() *tree[V] {
+	return &tree[V]{root: nil, size: 0}
+}
+
+// Snapshot returns a copy of the tree in O(1). The tree and the snapshot share their nodes,
+// which are copied on write by whichever of them is modified afterwards:
+// each write then copies the whole path from the root to the nodes it changes.
+// Snapshot counts as a write to the tree, so it must be called by the writer,
+// or serialized with the writes, and the snapshot handed over to the readers.
+func (t *tree[V]) Snapshot() TypedTree[V] {
+	return t.fork()
+}
+
+// fork returns a copy of the tree sharing its nodes, and stops both trees
+// from modifying the shared nodes in place.
+func (t *tree[V]) fork() *tree[V] {
+	t.gen = newGen()
+	return &tree[V]{root: t.root, size: t.size, gen: newGen()}
+}
+
+// newGen returns a generation that has not been handed out to any tree yet.
+func newGen() uint64 {
+	return atomic.AddUint64(&lastGen, 1)
+}
+
+// own marks the passed in artNode as owned by the tree, and returns it.
+func (t *tree[V]) own(n *artNode[V]) *artNode[V] {
+	n.gen = t.gen
+	return n
+}
+
+// writable returns the passed in artNode, or a copy of it if it is shared with another tree.
+// The caller must store a returned copy in place of the passed in artNode.
+func (t *tree[V]) writable(n *artNode[V]) *artNode[V] {
+	if n.gen != t.gen {
+		return n.clone(t.gen)
+	}
+	return n
+}
+
+// Search returns the value of the passed in key, or the zero value if not found.
+// Use Get to tell a missing key apart from a stored zero value.
+func (t *tree[V]) Search(key Key) V {
+	value, _ := t.Get(key)
+	return value
 }
 
-// Search returns the node that contains the passed in key, or nil if not found.
-func (t *tree) Search(key Key) Value {
-	return t.searchHelper(t.root, key, 0)
+// Get returns the value of the passed in key, and whether the key is present in the tree.
+func (t *tree[V]) Get(key Key) (V, bool) {
+	leaf := t.searchHelper(t.root, key, 0)
+	if leaf == nil {
+		var zero V
+		return zero, false
+	}
+	return leaf.leafNode().value, true
 }
 
-// searchHelper is a helper function for Search.
-func (t *tree) searchHelper(current *artNode, key []byte, depth int) interface{} {
+// searchHelper returns the leafNode that matches the passed in key, or nil if not found.
+func (t *tree[V]) searchHelper(current *artNode[V], key []byte, depth int) *artNode[V] {
 	for current != nil {
 		if current.isLeaf() {
 			if current.isMatch(key) {
-				return current.leafNode().value
+				return current
 			}
 			return nil
 		}
@@ -30,42 +87,162 @@ func (t *tree) searchHelper(current *artNode, key []byte, depth int) interface{}
 		}
 		depth += current.node().prefixLen
 
-		var keyChar byte
-		if depth < 0 || depth >= len(key) {
-			keyChar = byte(0)
-		} else {
-			keyChar = key[depth]
+		if depth == len(key) {
+			current = current.node().terminal
+			continue
 		}
-		current = *(current.findChild(keyChar))
+		current = *(current.findChild(key[depth]))
 		depth++
 	}
 
 	return nil
 }
 
-// Insert inserts the passed in value that is indexed by the passed in key into the tree.
-func (t *tree) Insert(key Key, value Value) {
-	t.insertHelper(&t.root, key, value, 0)
+// LongestPrefix returns the longest stored key that is a prefix of the passed in key,
+// together with its value.
+func (t *tree[V]) LongestPrefix(key Key) (Key, V, bool) {
+	return leafEntry(t.longestPrefixHelper(t.root, key, 0))
 }
 
-// insertHelper is a helper function for Insert.
-func (t *tree) insertHelper(currentRef **artNode, key []byte, value interface{}, depth int) {
-	if *currentRef == nil {
-		*currentRef = newLeafNode(key, value)
-		t.size++
+// longestPrefixHelper is a helper function for LongestPrefix.
+func (t *tree[V]) longestPrefixHelper(current *artNode[V], key []byte, depth int) *artNode[V] {
+	var match *artNode[V]
+	for current != nil {
+		if current.isLeaf() {
+			if bytes.HasPrefix(key, current.leafNode().key) {
+				match = current
+			}
+			break
+		}
+		if current.comparePrefix(key, depth) != 0 {
+			break
+		}
+		depth += current.node().prefixLen
+
+		if terminal := current.node().terminal; terminal != nil && bytes.HasPrefix(key, terminal.leafNode().key) {
+			match = terminal
+		}
+		if depth >= len(key) {
+			break
+		}
+		current = *(current.findChild(key[depth]))
+		depth++
+	}
+
+	return match
+}
+
+// Min returns the smallest key in the tree, together with its value.
+func (t *tree[V]) Min() (Key, V, bool) {
+	return leafEntry(t.root.minimum())
+}
+
+// Max returns the largest key in the tree, together with its value.
+func (t *tree[V]) Max() (Key, V, bool) {
+	return leafEntry(t.root.maximum())
+}
+
+// Floor returns the largest key that is less than or equal to the passed in key,
+// together with its value.
+func (t *tree[V]) Floor(key Key) (Key, V, bool) {
+	c := &cursor[V]{tree: t}
+	if !c.Seek(key) {
+		c.Last()
+	} else if !bytes.Equal(c.Key(), key) {
+		c.Prev()
+	}
+	return leafEntry(c.leaf)
+}
+
+// Ceiling returns the smallest key that is greater than or equal to the passed in key,
+// together with its value.
+func (t *tree[V]) Ceiling(key Key) (Key, V, bool) {
+	c := &cursor[V]{tree: t}
+	c.Seek(key)
+	return leafEntry(c.leaf)
+}
+
+// leafEntry returns the key and the value of the passed in leafNode, if not nil.
+func leafEntry[V any](leaf *artNode[V]) (Key, V, bool) {
+	if leaf == nil {
+		var zero V
+		return nil, zero, false
+	}
+	return leaf.leafNode().key, leaf.leafNode().value, true
+}
+
+// Insert inserts the passed in value that is indexed by the passed in key into the tree,
+// overwriting the value of a matching key.
+func (t *tree[V]) Insert(key Key, value V) {
+	t.Swap(key, value)
+}
+
+// Swap inserts the passed in value that is indexed by the passed in key into the tree,
+// and returns the value it replaced, if any.
+func (t *tree[V]) Swap(key Key, value V) (V, bool) {
+	leaf, inserted := t.insert(key, value, true)
+	if inserted {
+		var zero V
+		return zero, false
+	}
+	old := leaf.leafNode().value
+	leaf.leafNode().value = value
+	return old, true
+}
+
+// InsertIfAbsent inserts the passed in value that is indexed by the passed in key into the tree
+// if the key is not present yet. Otherwise it returns the existing value.
+func (t *tree[V]) InsertIfAbsent(key Key, value V) (V, bool) {
+	leaf, inserted := t.insert(key, value, false)
+	if inserted {
+		var zero V
+		return zero, true
+	}
+	return leaf.leafNode().value, false
+}
+
+// Upsert stores the value returned by the given function for the passed in key,
+// calling it with the current value of the key and whether the key exists.
+func (t *tree[V]) Upsert(key Key, fn TypedUpsertFunc[V]) {
+	var zero V
+	leaf, inserted := t.insert(key, zero, true)
+	if inserted {
+		leaf.leafNode().value = fn(zero, false)
 		return
 	}
-	current := *currentRef
+	leaf.leafNode().value = fn(leaf.leafNode().value, true)
+}
+
+// insert returns the leafNode that matches the passed in key, creating it with the passed in value
+// if it does not exist yet. An existing leafNode is only made writable if writeLeaf is set.
+func (t *tree[V]) insert(key Key, value V, writeLeaf bool) (*artNode[V], bool) {
+	root, leaf, inserted := t.insertHelper(t.root, key, value, 0, writeLeaf)
+	t.root = root
+	if inserted {
+		t.size++
+	}
+	return leaf, inserted
+}
+
+// insertHelper is a helper function of insert. It returns the artNode replacing the current one,
+// the leafNode that matches the passed in key, and whether that leafNode was inserted.
+// Nodes shared with another tree are only copied once a change below them is reported.
+func (t *tree[V]) insertHelper(current *artNode[V], key []byte, value V, depth int, writeLeaf bool) (*artNode[V], *artNode[V], bool) {
+	if current == nil {
+		newLeafNode := t.own(newLeafNode(key, value))
+		return newLeafNode, newLeafNode, true
+	}
 
 	if current.isLeaf() {
-		// NOTE: Currently, overwrite if the key matches.
 		if current.isMatch(key) {
-			current.leafNode().value = value
-			return
+			if writeLeaf {
+				current = t.writable(current)
+			}
+			return current, current, false
 		}
 
-		newNode4 := newNode4()
-		newLeafNode := newLeafNode(key, value)
+		newNode4 := t.own(newNode4[V]())
+		newLeafNode := t.own(newLeafNode(key, value))
 
 		limit := current.longestCommonPrefix(newLeafNode, depth)
 
@@ -73,30 +250,22 @@ func (t *tree) insertHelper(currentRef **artNode, key []byte, value interface{},
 
 		memcpy(newNode4.node().prefix[:], key[depth:], min(newNode4.node().prefixLen, maxPrefixLen))
 
-		if depth+newNode4.node().prefixLen < 0 || depth+newNode4.node().prefixLen >= len(current.leafNode().key) {
-			newNode4.addChild(0, current)
-		} else {
-			newNode4.addChild(current.leafNode().key[depth+newNode4.node().prefixLen], current)
-		}
-
-		if depth+newNode4.node().prefixLen < 0 || depth+newNode4.node().prefixLen >= len(key) {
-			newNode4.addChild(0, newLeafNode)
-		} else {
-			newNode4.addChild(key[depth+newNode4.node().prefixLen], newLeafNode)
-		}
-
-		*currentRef = newNode4
-		t.size++
+		// At most one of the keys ends right after the common prefix.
+		newNode4.addLeaf(current, depth+limit)
+		newNode4.addLeaf(newLeafNode, depth+limit)
+		newNode4.node().count = 2
 
-		return
+		return newNode4, newLeafNode, true
 	}
 
 	node := current.node()
 	if node.prefixLen != 0 {
 		mismatch := current.prefixMismatch(key, depth)
 		if mismatch != node.prefixLen {
-			newNode4 := newNode4()
-			*currentRef = newNode4
+			current = t.writable(current)
+			node = current.node()
+
+			newNode4 := t.own(newNode4[V]())
 			newNode4.node().prefixLen = mismatch
 
 			memcpy(newNode4.node().prefix[:], node.prefix[:], mismatch)
@@ -112,88 +281,145 @@ func (t *tree) insertHelper(currentRef **artNode, key []byte, value interface{},
 				memmove(node.prefix[:], minKey[depth+mismatch+1:], min(node.prefixLen, maxPrefixLen))
 			}
 
-			newLeafNode := newLeafNode(key, value)
-			newNode4.addChild(key[depth+mismatch], newLeafNode)
+			newLeafNode := t.own(newLeafNode(key, value))
+			newNode4.addLeaf(newLeafNode, depth+mismatch)
+			newNode4.node().count = node.count + 1
 
-			t.size++
-			return
+			return newNode4, newLeafNode, true
 		}
 		depth += node.prefixLen
 	}
 
-	next := current.findChild(key[depth])
-	if *next != nil {
-		t.insertHelper(next, key, value, depth+1)
-	} else {
-		current.addChild(key[depth], newLeafNode(key, value))
-		t.size++
+	if depth == len(key) {
+		terminal := node.terminal
+		if terminal != nil {
+			if writeLeaf && terminal.gen != t.gen {
+				current = t.writable(current)
+				terminal = t.writable(terminal)
+				current.node().terminal = terminal
+			}
+			return current, terminal, false
+		}
+		current = t.writable(current)
+		newLeafNode := t.own(newLeafNode(key, value))
+		current.node().terminal = newLeafNode
+		current.node().count++
+		return current, newLeafNode, true
+	}
+
+	next := *current.findChild(key[depth])
+	if next != nil {
+		child, leaf, inserted := t.insertHelper(next, key, value, depth+1, writeLeaf)
+		if child != next || inserted {
+			current = t.writable(current)
+			*current.findChild(key[depth]) = child
+			if inserted {
+				current.node().count++
+			}
+		}
+		return current, leaf, inserted
 	}
+	current = t.writable(current)
+	newLeafNode := t.own(newLeafNode(key, value))
+	current.node().count++
+	current.addChild(key[depth], newLeafNode)
+	return current, newLeafNode, true
 }
 
 // Delete deletes the child of the passed in key.
-func (t *tree) Delete(key []byte) bool {
-	return t.deleteHelper(&t.root, key, 0)
+func (t *tree[V]) Delete(key []byte) bool {
+	_, ok := t.Remove(key)
+	return ok
 }
 
-// deleteHelper is a helper function of Delete.
-func (t *tree) deleteHelper(currentRef **artNode, key []byte, depth int) bool {
-	if t == nil || *currentRef == nil || len(key) == 0 {
-		return false
+// Remove deletes the child of the passed in key, and returns its value.
+func (t *tree[V]) Remove(key Key) (V, bool) {
+	if t == nil {
+		var zero V
+		return zero, false
+	}
+	root, leaf := t.deleteHelper(t.root, key, 0)
+	if leaf == nil {
+		var zero V
+		return zero, false
+	}
+	t.root = root
+	t.size--
+	return leaf.leafNode().value, true
+}
+
+// deleteHelper deletes the leafNode that matches the passed in key below the current artNode.
+// It returns the artNode replacing the current one, and the deleted leafNode or nil if not found.
+// Nodes shared with another tree are only copied once a deletion below them is reported.
+func (t *tree[V]) deleteHelper(current *artNode[V], key []byte, depth int) (*artNode[V], *artNode[V]) {
+	if current == nil {
+		return nil, nil
 	}
 
-	current := *currentRef
 	if current.isLeaf() {
 		if current.isMatch(key) {
-			*currentRef = nil
-			t.size--
-			return true
+			return nil, current
 		}
+		return current, nil
 	}
 
 	if current.node().prefixLen != 0 {
 		mismatch := current.prefixMismatch(key, depth)
 		if mismatch != current.node().prefixLen {
-			return false
+			return current, nil
 		}
 		depth += current.node().prefixLen
 	}
 
-	var keyChar byte
-	if depth < 0 || depth >= len(key) {
-		keyChar = byte(0)
-	} else {
-		keyChar = key[depth]
+	if depth == len(key) {
+		terminal := current.node().terminal
+		if terminal == nil {
+			return current, nil
+		}
+		current = t.writable(current)
+		current.node().count--
+		current.removeTerminal()
+		return current, terminal
 	}
-	next := current.findChild(keyChar)
 
-	if *next != nil && (*next).isLeaf() && (*next).isMatch(key) {
-		current.RemoveChild(keyChar)
-		t.size--
-		return true
+	next := *current.findChild(key[depth])
+	child, leaf := t.deleteHelper(next, key, depth+1)
+	if leaf == nil {
+		return current, nil
 	}
 
-	return t.deleteHelper(next, key, depth+1)
+	current = t.writable(current)
+	current.node().count--
+	if child == nil {
+		current.RemoveChild(key[depth])
+	} else {
+		*current.findChild(key[depth]) = child
+	}
+	return current, leaf
 }
 
 // Each iterate the whole tree with the lexicographical order,
 // and will call the given callback for each tree node.
-func (t *tree) Each(callback Callback) {
+func (t *tree[V]) Each(callback TypedCallback[V]) {
 	t.eachHelper(t.root, callback)
 }
 
 // Size returns the number of leafNodes (key-value) in the tree.
-func (t *tree) Size() int {
+func (t *tree[V]) Size() int {
 	return int(t.size)
 }
 
 // eachHelper is a helper function of Each.
-func (t *tree) eachHelper(current *artNode, callback Callback) {
+func (t *tree[V]) eachHelper(current *artNode[V], callback TypedCallback[V]) {
 	if current == nil {
 		return
 	}
 
 	callback(current)
 
+	if !current.isLeaf() && current.node().terminal != nil {
+		t.eachHelper(current.node().terminal, callback)
+	}
 	switch current.nodeType {
 	case Node4:
 		t.eachChildren(current.node4().children[:], callback)
@@ -215,7 +441,7 @@ func (t *tree) eachHelper(current *artNode, callback Callback) {
 }
 
 // eachChildren is used by eachHelper to iterate children of artNode.
-func (t *tree) eachChildren(children []*artNode, callback Callback) {
+func (t *tree[V]) eachChildren(children []*artNode[V], callback TypedCallback[V]) {
 	for _, child := range children {
 		if child != nil {
 			t.eachHelper(child, callback)
@@ -223,6 +449,175 @@ func (t *tree) eachChildren(children []*artNode, callback Callback) {
 	}
 }
 
+// EachWith iterates the whole tree like Each, visiting nodes according to the passed in options.
+func (t *tree[V]) EachWith(opts WalkOptions, callback TypedCallback[V]) {
+	t.walkHelper(t.root, opts, func(node TypedNode[V]) WalkAction {
+		callback(node)
+		return WalkContinue
+	})
+}
+
+// Walk iterates the whole tree with the lexicographical order like Each,
+// and lets the given callback skip the children of a node or stop the traversal.
+func (t *tree[V]) Walk(fn TypedWalkFunc[V]) {
+	t.walkHelper(t.root, WalkOptions{}, fn)
+}
+
+// WalkWith iterates the whole tree like Walk, visiting nodes according to the passed in options.
+func (t *tree[V]) WalkWith(opts WalkOptions, fn TypedWalkFunc[V]) {
+	t.walkHelper(t.root, opts, fn)
+}
+
+// walkHelper is a helper function of Walk,
+// it returns false once the traversal is stopped.
+func (t *tree[V]) walkHelper(current *artNode[V], opts WalkOptions, fn TypedWalkFunc[V]) bool {
+	if current == nil {
+		return true
+	}
+
+	visit := opts.Nodes == WalkAllNodes || (opts.Nodes == WalkLeaves) == current.isLeaf()
+	if visit && !opts.PostOrder {
+		switch fn(current) {
+		case WalkStop:
+			return false
+		case WalkSkipChildren:
+			return true
+		}
+	}
+
+	if !current.isLeaf() {
+		if opts.Descending {
+			for pos, child := current.prevChild(node256Max); child != nil; pos, child = current.prevChild(pos - 1) {
+				if !t.walkHelper(child, opts, fn) {
+					return false
+				}
+			}
+		} else {
+			for pos, child := current.nextChild(terminalPos); child != nil; pos, child = current.nextChild(pos + 1) {
+				if !t.walkHelper(child, opts, fn) {
+					return false
+				}
+			}
+		}
+	}
+
+	if visit && opts.PostOrder {
+		return fn(current) != WalkStop
+	}
+	return true
+}
+
+// Range calls the given callback for each leafNode whose key lies between lo and hi,
+// in lexicographical order. The bounds are interpreted according to opts,
+// and the key of an Unbounded bound is ignored.
+func (t *tree[V]) Range(lo, hi Key, opts RangeOptions, callback TypedCallback[V]) {
+	t.rangeHelper(lo, hi, opts, func(leaf *artNode[V]) bool {
+		callback(leaf)
+		return true
+	})
+}
+
+// rangeHelper is a helper function of Range,
+// it stops as soon as the given function returns false.
+func (t *tree[V]) rangeHelper(lo, hi Key, opts RangeOptions, fn func(leaf *artNode[V]) bool) {
+	c := &cursor[V]{tree: t}
+
+	var ok bool
+	if opts.Lo == Unbounded {
+		ok = c.First()
+	} else {
+		ok = c.Seek(lo)
+		if ok && opts.Lo == Exclusive && bytes.Equal(c.Key(), lo) {
+			ok = c.Next()
+		}
+	}
+
+	for ; ok; ok = c.Next() {
+		if opts.Hi != Unbounded {
+			cmp := bytes.Compare(c.Key(), hi)
+			if cmp > 0 || (cmp == 0 && opts.Hi == Exclusive) {
+				return
+			}
+		}
+		if !fn(c.leaf) {
+			return
+		}
+	}
+}
+
+// ScanPrefix calls the given callback for each leafNode whose key starts with
+// the passed in prefix, in lexicographical order.
+func (t *tree[V]) ScanPrefix(prefix Key, callback TypedCallback[V]) {
+	t.eachLeaf(t.prefixHelper(t.root, prefix, 0), callback)
+}
+
+// CountPrefix returns the number of leafNodes whose key starts with the passed in prefix,
+// read from the leaf count of the subtree holding them.
+func (t *tree[V]) CountPrefix(prefix Key) int {
+	current := t.prefixHelper(t.root, prefix, 0)
+	if current == nil {
+		return 0
+	}
+	return current.leafCount()
+}
+
+// prefixHelper returns the topmost artNode whose subtree holds exactly
+// the leafNodes with keys starting with the passed in prefix, or nil if there are none.
+func (t *tree[V]) prefixHelper(current *artNode[V], prefix []byte, depth int) *artNode[V] {
+	for current != nil {
+		if current.isLeaf() {
+			if bytes.HasPrefix(current.leafNode().key, prefix) {
+				return current
+			}
+			return nil
+		}
+		if depth >= len(prefix) {
+			return current
+		}
+
+		node := current.node()
+		var minKey []byte
+		for i := 0; i < node.prefixLen && depth+i < len(prefix); i++ {
+			var p byte
+			if i < maxPrefixLen {
+				p = node.prefix[i]
+			} else {
+				if minKey == nil {
+					minKey = current.minimum().leafNode().key
+				}
+				p = minKey[depth+i]
+			}
+			if p != prefix[depth+i] {
+				return nil
+			}
+		}
+		depth += node.prefixLen
+		if depth >= len(prefix) {
+			return current
+		}
+
+		current = *(current.findChild(prefix[depth]))
+		depth++
+	}
+
+	return nil
+}
+
+// eachLeaf calls the given callback for each leafNode below the passed in artNode,
+// in lexicographical order.
+func (t *tree[V]) eachLeaf(current *artNode[V], callback TypedCallback[V]) {
+	if current == nil {
+		return
+	}
+	if current.isLeaf() {
+		callback(current)
+		return
+	}
+	for pos, child := current.nextChild(terminalPos); child != nil; pos, child = current.nextChild(pos + 1) {
+		t.eachLeaf(child, callback)
+	}
+}
+
 // memcpy copies numBytes bytes from src to dst.
 func memcpy(dst []byte, src []byte, numBytes int) {
 	for i := 0; i < numBytes && i < len(src) && i < len(dst); i++ {
@@ -1,8 +1,10 @@
 package art
 
 import (
+	"bytes"
 	"encoding/binary"
 	"math/rand"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -11,7 +13,7 @@ import (
 )
 
 func TestArtTreeInsert(t *testing.T) {
-	tree := newArt()
+	tree := newArt[Value]()
 	tree.Insert(Key("hello"), "world")
 
 	assert.Equal(t, int64(1), tree.size)
@@ -19,7 +21,7 @@ func TestArtTreeInsert(t *testing.T) {
 }
 
 func TestArtTreeInsertAndSearch(t *testing.T) {
-	tree := newArt()
+	tree := newArt[Value]()
 
 	tree.Insert(Key("hello"), "world")
 	res := tree.Search(Key("hello"))
@@ -28,7 +30,7 @@ func TestArtTreeInsertAndSearch(t *testing.T) {
 }
 
 func TestArtTreeInsert2AndSearch(t *testing.T) {
-	tree := newArt()
+	tree := newArt[Value]()
 
 	tree.Insert(Key("hello"), "world")
 	tree.Insert(Key("yo"), "earth")
@@ -43,7 +45,7 @@ func TestArtTreeInsert2AndSearch(t *testing.T) {
 }
 
 func TestArtTreeInsert2WithSimilarPrefix(t *testing.T) {
-	tree := newArt()
+	tree := newArt[Value]()
 
 	tree.Insert(Key("a"), "a")
 	tree.Insert(Key("aa"), "aa")
@@ -55,7 +57,7 @@ func TestArtTreeInsert2WithSimilarPrefix(t *testing.T) {
 }
 
 func TestArtTreeInsert3AndSearchWords(t *testing.T) {
-	tree := newArt()
+	tree := newArt[Value]()
 
 	searchTerms := []string{"A", "a", "aa"}
 
@@ -81,7 +83,7 @@ func TestArtTreeInsertAndGrowToBiggerNode(t *testing.T) {
 	}
 
 	for _, data := range testData {
-		tree := newArt()
+		tree := newArt[Value]()
 		for i := byte(0); i < data.totalNodes; i++ {
 			tree.Insert(Key{i}, i)
 		}
@@ -91,7 +93,7 @@ func TestArtTreeInsertAndGrowToBiggerNode(t *testing.T) {
 }
 
 func TestInsertManyWordsAndEnsureSearchResultAndMinimumMaximum(t *testing.T) {
-	tree := newArt()
+	tree := newArt[Value]()
 
 	words := testdata.LoadTestFile("testdata/data/words.txt")
 
@@ -113,7 +115,7 @@ func TestInsertManyWordsAndEnsureSearchResultAndMinimumMaximum(t *testing.T) {
 }
 
 func TestInsertManyUUIDsAndEnsureSearchAndMinimumMaximum(t *testing.T) {
-	tree := newArt()
+	tree := newArt[Value]()
 
 	uuids := testdata.LoadTestFile("testdata/data/uuid.txt")
 
@@ -138,7 +140,7 @@ func TestInsertManyUUIDsAndEnsureSearchAndMinimumMaximum(t *testing.T) {
 }
 
 func TestInsertAndRemove1(t *testing.T) {
-	tree := newArt()
+	tree := newArt[Value]()
 
 	tree.Insert(Key("test"), []byte("data"))
 
@@ -149,7 +151,7 @@ func TestInsertAndRemove1(t *testing.T) {
 }
 
 func TestInsert2AndRemove1AndRootShouldBeLeafNode(t *testing.T) {
-	tree := newArt()
+	tree := newArt[Value]()
 
 	tree.Insert(Key("test"), []byte("data"))
 	tree.Insert(Key("test2"), []byte("data"))
@@ -162,7 +164,7 @@ func TestInsert2AndRemove1AndRootShouldBeLeafNode(t *testing.T) {
 }
 
 func TestInsert2AndRemove2AndRootShouldBeNil(t *testing.T) {
-	tree := newArt()
+	tree := newArt[Value]()
 
 	tree.Insert(Key("test"), []byte("data"))
 	tree.Insert(Key("test2"), []byte("data"))
@@ -175,7 +177,7 @@ func TestInsert2AndRemove2AndRootShouldBeNil(t *testing.T) {
 }
 
 func TestInsert5AndRemove1AndRootShouldBeNode4(t *testing.T) {
-	tree := newArt()
+	tree := newArt[Value]()
 
 	for i := 0; i < 5; i++ {
 		tree.Insert(Key{byte(i)}, []byte{byte(i)})
@@ -191,7 +193,7 @@ func TestInsert5AndRemove1AndRootShouldBeNode4(t *testing.T) {
 }
 
 func TestInsert5AndRemove5AndRootShouldBeNil(t *testing.T) {
-	tree := newArt()
+	tree := newArt[Value]()
 
 	for i := 0; i < 5; i++ {
 		tree.Insert(Key{byte(i)}, []byte{byte(i)})
@@ -211,7 +213,7 @@ func TestInsert5AndRemove5AndRootShouldBeNil(t *testing.T) {
 }
 
 func TestInsert17AndRemove1AndRootShouldBeNode16(t *testing.T) {
-	tree := newArt()
+	tree := newArt[Value]()
 
 	for i := 0; i < 17; i++ {
 		tree.Insert(Key{byte(i)}, []byte{byte(i)})
@@ -227,7 +229,7 @@ func TestInsert17AndRemove1AndRootShouldBeNode16(t *testing.T) {
 }
 
 func TestInsert17AndRemove17AndRootShouldBeNil(t *testing.T) {
-	tree := newArt()
+	tree := newArt[Value]()
 
 	for i := 0; i < 17; i++ {
 		tree.Insert(Key{byte(i)}, []byte{byte(i)})
@@ -247,7 +249,7 @@ func TestInsert17AndRemove17AndRootShouldBeNil(t *testing.T) {
 }
 
 func TestInsert49AndRemove1AndRootShouldBeNode48(t *testing.T) {
-	tree := newArt()
+	tree := newArt[Value]()
 
 	for i := 0; i < 49; i++ {
 		tree.Insert(Key{byte(i)}, []byte{byte(i)})
@@ -264,7 +266,7 @@ func TestInsert49AndRemove1AndRootShouldBeNode48(t *testing.T) {
 }
 
 func TestInsert49AndRemove49AndRootShouldBeNil(t *testing.T) {
-	tree := newArt()
+	tree := newArt[Value]()
 
 	for i := 0; i < 49; i++ {
 		tree.Insert(Key{byte(i)}, []byte{byte(i)})
@@ -283,7 +285,7 @@ func TestInsert49AndRemove49AndRootShouldBeNil(t *testing.T) {
 }
 
 func TestEachPreOrder(t *testing.T) {
-	tree := newArt()
+	tree := newArt[Value]()
 	tree.Insert(Key("1"), []byte("1"))
 	tree.Insert(Key("2"), []byte("2"))
 
@@ -304,7 +306,7 @@ func TestEachPreOrder(t *testing.T) {
 }
 
 func TestEachNode48(t *testing.T) {
-	tree := newArt()
+	tree := newArt[Value]()
 
 	for i := 48; i > 0; i-- {
 		tree.Insert(Key{byte(i)}, []byte{byte(i)})
@@ -326,7 +328,7 @@ func TestEachNode48(t *testing.T) {
 }
 
 func TestEachFullIterationExpectCountOfAllTypes(t *testing.T) {
-	tree := newArt()
+	tree := newArt[Value]()
 
 	words := testdata.LoadTestFile("testdata/data/words.txt")
 
@@ -357,14 +359,14 @@ func TestEachFullIterationExpectCountOfAllTypes(t *testing.T) {
 	})
 
 	assert.Equalf(t, 235886, leafCount, "leafNode count must be equal to 235886")
-	assert.Equalf(t, 111616, node4Count, "node4 count must be equal to 111616")
-	assert.Equalf(t, 12181, node16Count, "node16 count must be equal to 12181")
-	assert.Equalf(t, 458, node48Count, "node48 count must be equal to 458")
+	assert.Equalf(t, 113419, node4Count, "node4 count must be equal to 113419")
+	assert.Equalf(t, 10433, node16Count, "node16 count must be equal to 10433")
+	assert.Equalf(t, 403, node48Count, "node48 count must be equal to 403")
 	assert.Equalf(t, 1, node256Count, "node256 must be the only one")
 }
 
 func TestInsertManyWordsAndRemoveThemAll(t *testing.T) {
-	tree := newArt()
+	tree := newArt[Value]()
 
 	words := testdata.LoadTestFile("testdata/data/words.txt")
 
@@ -387,7 +389,7 @@ func TestInsertManyWordsAndRemoveThemAll(t *testing.T) {
 }
 
 func TestInsertManyUUIDsAndRemoveThemAll(t *testing.T) {
-	tree := newArt()
+	tree := newArt[Value]()
 
 	uuids := testdata.LoadTestFile("testdata/data/uuid.txt")
 
@@ -412,7 +414,7 @@ func TestInsertManyUUIDsAndRemoveThemAll(t *testing.T) {
 func TestInsertWithSameByteSliceAddress(t *testing.T) {
 	rand.Seed(42)
 	key := make([]byte, 8)
-	tree := newArt()
+	tree := newArt[Value]()
 
 	keys := make(map[string]bool)
 
@@ -434,7 +436,7 @@ func BenchmarkWordsTreeInsert(b *testing.B) {
 	words := testdata.LoadTestFile("testdata/data/words.txt")
 	b.ResetTimer()
 	for n := 0; n < b.N; n++ {
-		tree := newArt()
+		tree := newArt[Value]()
 		for _, w := range words {
 			tree.Insert(w, w)
 		}
@@ -443,7 +445,7 @@ func BenchmarkWordsTreeInsert(b *testing.B) {
 
 func BenchmarkWordsTreeSearch(b *testing.B) {
 	words := testdata.LoadTestFile("testdata/data/words.txt")
-	tree := newArt()
+	tree := newArt[Value]()
 	for _, w := range words {
 		tree.Insert(w, w)
 	}
@@ -457,7 +459,7 @@ func BenchmarkWordsTreeSearch(b *testing.B) {
 
 func BenchmarkWordsTreeForEach(b *testing.B) {
 	words := testdata.LoadTestFile("testdata/data/words.txt")
-	tree := newArt()
+	tree := newArt[Value]()
 	for _, w := range words {
 		tree.Insert(w, w)
 	}
@@ -467,14 +469,14 @@ func BenchmarkWordsTreeForEach(b *testing.B) {
 	tree.Each(func(n Node) {
 		nodeTypes[n.NodeType()]++
 	})
-	assert.Equal(b, map[NodeType]int{LeafNode: 235886, Node4: 111616, Node16: 12181, Node48: 458, Node256: 1}, nodeTypes)
+	assert.Equal(b, map[NodeType]int{LeafNode: 235886, Node4: 113419, Node16: 10433, Node48: 403, Node256: 1}, nodeTypes)
 }
 
 func BenchmarkUUIDsTreeInsert(b *testing.B) {
 	words := testdata.LoadTestFile("testdata/data/uuid.txt")
 	b.ResetTimer()
 	for n := 0; n < b.N; n++ {
-		tree := newArt()
+		tree := newArt[Value]()
 		for _, w := range words {
 			tree.Insert(w, w)
 		}
@@ -483,7 +485,7 @@ func BenchmarkUUIDsTreeInsert(b *testing.B) {
 
 func BenchmarkUUIDsTreeSearch(b *testing.B) {
 	words := testdata.LoadTestFile("testdata/data/uuid.txt")
-	tree := newArt()
+	tree := newArt[Value]()
 	for _, w := range words {
 		tree.Insert(w, w)
 	}
@@ -497,7 +499,7 @@ func BenchmarkUUIDsTreeSearch(b *testing.B) {
 
 func BenchmarkUUIDsTreeEach(b *testing.B) {
 	words := testdata.LoadTestFile("testdata/data/uuid.txt")
-	tree := newArt()
+	tree := newArt[Value]()
 	for _, w := range words {
 		tree.Insert(w, w)
 	}
@@ -509,3 +511,710 @@ func BenchmarkUUIDsTreeEach(b *testing.B) {
 	})
 	assert.Equal(b, map[NodeType]int{LeafNode: 500000, Node4: 103602, Node16: 56030}, nodeTypes)
 }
+
+func TestRangeBounds(t *testing.T) {
+	tree := newArt[Value]()
+	for i := byte(0); i < 100; i++ {
+		tree.Insert(Key{0, i}, int(i))
+	}
+
+	var testData = []struct {
+		lo, hi   byte
+		opts     RangeOptions
+		expected []int
+	}{
+		{10, 13, RangeOptions{}, []int{10, 11, 12, 13}},
+		{10, 13, RangeOptions{Lo: Exclusive}, []int{11, 12, 13}},
+		{10, 13, RangeOptions{Hi: Exclusive}, []int{10, 11, 12}},
+		{10, 13, RangeOptions{Lo: Exclusive, Hi: Exclusive}, []int{11, 12}},
+		{0, 2, RangeOptions{Lo: Unbounded}, []int{0, 1, 2}},
+		{97, 0, RangeOptions{Hi: Unbounded}, []int{97, 98, 99}},
+		{13, 10, RangeOptions{}, nil},
+		{10, 10, RangeOptions{Hi: Exclusive}, nil},
+	}
+
+	for _, data := range testData {
+		var res []int
+		tree.Range(Key{0, data.lo}, Key{0, data.hi}, data.opts, func(node Node) {
+			res = append(res, node.Value().(int))
+		})
+		assert.Equal(t, data.expected, res)
+	}
+}
+
+func TestRangeBigEndianKeys(t *testing.T) {
+	tree := newArt[Value]()
+	for i := uint64(0); i < 10000; i++ {
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, i*1000)
+		tree.Insert(key, i)
+	}
+
+	lo, hi := make([]byte, 8), make([]byte, 8)
+	binary.BigEndian.PutUint64(lo, 1500)
+	binary.BigEndian.PutUint64(hi, 5000000)
+
+	var res []uint64
+	tree.Range(lo, hi, RangeOptions{}, func(node Node) {
+		res = append(res, node.Value().(uint64))
+	})
+
+	assert.Len(t, res, 4999)
+	assert.Equal(t, uint64(2), res[0])
+	assert.Equal(t, uint64(5000), res[len(res)-1])
+}
+
+func TestScanPrefix(t *testing.T) {
+	tree := newArt[Value]()
+	for _, k := range []string{"tenant/1/a", "tenant/42/a", "tenant/42/b", "tenant/420/a", "tenant/5", "user/42"} {
+		tree.Insert(Key(k), k)
+	}
+
+	var testData = []struct {
+		prefix   string
+		expected []string
+	}{
+		{"tenant/42/", []string{"tenant/42/a", "tenant/42/b"}},
+		{"tenant/42", []string{"tenant/42/a", "tenant/42/b", "tenant/420/a"}},
+		{"tenant/4", []string{"tenant/42/a", "tenant/42/b", "tenant/420/a"}},
+		{"ten", []string{"tenant/1/a", "tenant/42/a", "tenant/42/b", "tenant/420/a", "tenant/5"}},
+		{"user/42", []string{"user/42"}},
+		{"user/421", nil},
+		{"tenant/43", nil},
+		{"x", nil},
+	}
+
+	for _, data := range testData {
+		var res []string
+		tree.ScanPrefix(Key(data.prefix), func(node Node) {
+			res = append(res, node.Value().(string))
+		})
+		assert.Equal(t, data.expected, res, data.prefix)
+		assert.Equal(t, len(data.expected), tree.CountPrefix(Key(data.prefix)), data.prefix)
+	}
+	assert.Equal(t, 6, tree.CountPrefix(nil))
+}
+
+func TestScanPrefixInsideLongCompressedPath(t *testing.T) {
+	tree := newArt[Value]()
+	tree.Insert(Key("averyveryverylongprefix/1"), 1)
+	tree.Insert(Key("averyveryverylongprefix/2"), 2)
+	tree.Insert(Key("b"), 3)
+
+	assert.Equal(t, 2, tree.CountPrefix(Key("averyvery")))
+	assert.Equal(t, 2, tree.CountPrefix(Key("averyveryverylong")))
+	assert.Equal(t, 2, tree.CountPrefix(Key("averyveryverylongprefix/")))
+	assert.Equal(t, 1, tree.CountPrefix(Key("averyveryverylongprefix/2")))
+	assert.Zero(t, tree.CountPrefix(Key("averyveryverylongprefiy")))
+	assert.Zero(t, tree.CountPrefix(Key("averyveryverylongprefix/3")))
+}
+
+func TestScanPrefixManyWords(t *testing.T) {
+	tree := newArt[Value]()
+
+	words := testdata.LoadTestFile("testdata/data/words.txt")
+	for _, w := range words {
+		tree.Insert(w, w)
+	}
+
+	for _, prefix := range []string{"a", "re", "inter", "z", "Q", "antidis"} {
+		expected := 0
+		for _, w := range words {
+			if bytes.HasPrefix(w, Key(prefix)) {
+				expected++
+			}
+		}
+		var prev Key
+		count := 0
+		tree.ScanPrefix(Key(prefix), func(node Node) {
+			assert.True(t, bytes.HasPrefix(node.Key(), Key(prefix)))
+			assert.True(t, bytes.Compare(prev, node.Key()) < 0)
+			prev = node.Key()
+			count++
+		})
+		assert.Equal(t, expected, count, prefix)
+	}
+}
+
+func TestLongestPrefix(t *testing.T) {
+	tree := newArt[Value]()
+	for _, k := range []string{"/", "/api", "/api/v1", "/api/v1/users", "/static/css", "/staticfiles"} {
+		tree.Insert(Key(k), k)
+	}
+
+	var testData = []struct {
+		key      string
+		expected string
+		ok       bool
+	}{
+		{"/api/v1/users/42", "/api/v1/users", true},
+		{"/api/v1/user", "/api/v1", true},
+		{"/api/v1", "/api/v1", true},
+		{"/api/v2", "/api", true},
+		{"/apix", "/api", true},
+		{"/static/css/main.css", "/static/css", true},
+		{"/static/js", "/", true},
+		{"/", "/", true},
+		{"", "", false},
+		{"api", "", false},
+	}
+
+	for _, data := range testData {
+		matchedKey, value, ok := tree.LongestPrefix(Key(data.key))
+		assert.Equal(t, data.ok, ok, data.key)
+		if data.ok {
+			assert.Equal(t, Key(data.expected), matchedKey, data.key)
+			assert.Equal(t, data.expected, value, data.key)
+		}
+	}
+}
+
+func TestLongestPrefixManyWords(t *testing.T) {
+	tree := newArt[Value]()
+
+	words := testdata.LoadTestFile("testdata/data/words.txt")
+	for _, w := range words {
+		tree.Insert(w, w)
+	}
+
+	for _, w := range words[:5000] {
+		query := append(append(Key{}, w...), "zzz"...)
+		matchedKey, _, ok := tree.LongestPrefix(query)
+		assert.True(t, ok)
+		assert.True(t, len(matchedKey) >= len(w), string(w))
+	}
+}
+
+func TestGetDistinguishesMissingFromNil(t *testing.T) {
+	tree := newArt[Value]()
+	tree.Insert(Key("present"), nil)
+	tree.Insert(Key("other"), "value")
+
+	value, ok := tree.Get(Key("present"))
+	assert.True(t, ok)
+	assert.Nil(t, value)
+
+	value, ok = tree.Get(Key("missing"))
+	assert.False(t, ok)
+	assert.Nil(t, value)
+
+	value, ok = tree.Get(Key("other"))
+	assert.True(t, ok)
+	assert.Equal(t, "value", value)
+
+	assert.True(t, tree.Delete(Key("present")))
+	_, ok = tree.Get(Key("present"))
+	assert.False(t, ok)
+	assert.Equal(t, 1, tree.Size())
+}
+
+func TestGetAndDeleteMissingKeys(t *testing.T) {
+	tree := newArt[Value]()
+	tree.Insert(Key("averyveryverylongprefix/1"), nil)
+
+	assert.False(t, tree.Delete(Key("averyveryverylongprefix/2")))
+	assert.Equal(t, 1, tree.Size())
+
+	tree.Insert(Key("averyveryverylongprefix/2"), nil)
+
+	_, ok := tree.Get(Key("averyveryverylong"))
+	assert.False(t, ok)
+	assert.False(t, tree.Delete(Key("averyveryverylong")))
+	_, ok = tree.Get(Key("averyveryverylongprefix/2"))
+	assert.True(t, ok)
+	assert.Equal(t, 2, tree.Size())
+}
+
+func TestSwapReturnsPreviousValue(t *testing.T) {
+	tree := newArt[Value]()
+
+	old, replaced := tree.Swap(Key("key"), 1)
+	assert.False(t, replaced)
+	assert.Nil(t, old)
+
+	old, replaced = tree.Swap(Key("key"), 2)
+	assert.True(t, replaced)
+	assert.Equal(t, 1, old)
+
+	old, replaced = tree.Swap(Key("key2"), 3)
+	assert.False(t, replaced)
+	assert.Nil(t, old)
+
+	assert.Equal(t, 2, tree.Search(Key("key")))
+	assert.Equal(t, 2, tree.Size())
+}
+
+func TestRemoveReturnsPreviousValue(t *testing.T) {
+	tree := newArt[Value]()
+	for i := 0; i < 20; i++ {
+		tree.Insert(Key{'k', byte(i)}, i)
+	}
+
+	old, ok := tree.Remove(Key{'k', 7})
+	assert.True(t, ok)
+	assert.Equal(t, 7, old)
+
+	old, ok = tree.Remove(Key{'k', 7})
+	assert.False(t, ok)
+	assert.Nil(t, old)
+
+	old, ok = tree.Remove(Key{'x'})
+	assert.False(t, ok)
+	assert.Nil(t, old)
+
+	assert.Equal(t, 19, tree.Size())
+}
+
+func TestInsertIfAbsent(t *testing.T) {
+	tree := newArt[Value]()
+
+	existing, inserted := tree.InsertIfAbsent(Key("key"), 1)
+	assert.True(t, inserted)
+	assert.Nil(t, existing)
+
+	existing, inserted = tree.InsertIfAbsent(Key("key"), 2)
+	assert.False(t, inserted)
+	assert.Equal(t, 1, existing)
+
+	assert.Equal(t, 1, tree.Search(Key("key")))
+	assert.Equal(t, 1, tree.Size())
+}
+
+func TestUpsertCounters(t *testing.T) {
+	tree := newArt[Value]()
+
+	words := testdata.LoadTestFile("testdata/data/words.txt")
+	counts := make(map[byte]int)
+	for _, w := range words[:10000] {
+		counts[w[0]]++
+		tree.Upsert(w[:1], func(old Value, exists bool) Value {
+			if !exists {
+				return 1
+			}
+			return old.(int) + 1
+		})
+	}
+
+	assert.Equal(t, len(counts), tree.Size())
+	for b, count := range counts {
+		assert.Equal(t, count, tree.Search(Key{b}))
+	}
+}
+
+func TestTypedTree(t *testing.T) {
+	tree := NewTyped[uint64]()
+
+	tree.Insert(Key("a"), 1)
+	tree.Insert(Key("b"), 2)
+	tree.Upsert(Key("a"), func(old uint64, exists bool) uint64 {
+		return old + 10
+	})
+
+	value, ok := tree.Get(Key("a"))
+	assert.True(t, ok)
+	assert.Equal(t, uint64(11), value)
+
+	value, ok = tree.Get(Key("c"))
+	assert.False(t, ok)
+	assert.Zero(t, value)
+	assert.Zero(t, tree.Search(Key("c")))
+
+	var sum uint64
+	tree.Each(func(node TypedNode[uint64]) {
+		sum += node.Value()
+	})
+	assert.Equal(t, uint64(13), sum)
+}
+
+func TestSnapshotIsolation(t *testing.T) {
+	tree := newArt[Value]()
+	for i := 0; i < 100; i++ {
+		tree.Insert(Key{'k', byte(i)}, i)
+	}
+
+	snapshot := tree.Snapshot()
+
+	for i := 0; i < 100; i += 2 {
+		tree.Delete(Key{'k', byte(i)})
+	}
+	for i := 0; i < 100; i += 3 {
+		tree.Insert(Key{'k', byte(i)}, -i)
+	}
+	tree.Insert(Key("j"), "j")
+
+	assert.Equal(t, 100, snapshot.Size())
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, i, snapshot.Search(Key{'k', byte(i)}))
+	}
+	_, ok := snapshot.Get(Key("j"))
+	assert.False(t, ok)
+
+	snapshot.Insert(Key{'k', 0}, "snapshot")
+	for i := 0; i < 100; i++ {
+		value, ok := tree.Get(Key{'k', byte(i)})
+		switch {
+		case i%3 == 0:
+			assert.Equal(t, -i, value)
+		case i%2 == 0:
+			assert.False(t, ok)
+		default:
+			assert.Equal(t, i, value)
+		}
+	}
+}
+
+func TestSnapshotTerminalWrites(t *testing.T) {
+	tree := newArt[Value]()
+	tree.Insert(Key("a"), 1)
+	tree.Insert(Key("ab"), 2)
+	tree.Insert(Key("ac"), 3)
+
+	snapshot := tree.Snapshot()
+	tree.Insert(Key("a"), 10)
+	tree.Upsert(Key("ab"), func(old Value, _ bool) Value { return old.(int) * 10 })
+	_, inserted := tree.InsertIfAbsent(Key("ac"), 30)
+	assert.False(t, inserted)
+
+	assert.Equal(t, 1, snapshot.Search(Key("a")))
+	assert.Equal(t, 2, snapshot.Search(Key("ab")))
+	assert.Equal(t, 10, tree.Search(Key("a")))
+	assert.Equal(t, 20, tree.Search(Key("ab")))
+	assert.Equal(t, 3, tree.Search(Key("ac")))
+}
+
+func TestSnapshotManyWords(t *testing.T) {
+	tree := newArt[Value]()
+
+	words := testdata.LoadTestFile("testdata/data/words.txt")
+	for _, w := range words {
+		tree.Insert(w, w)
+	}
+
+	snapshot := tree.Snapshot()
+	for i, w := range words {
+		if i%2 == 0 {
+			tree.Delete(w)
+		} else {
+			tree.Insert(w, i)
+		}
+	}
+	second := tree.Snapshot()
+	for _, w := range words {
+		tree.Delete(w)
+	}
+
+	assert.Zero(t, tree.Size())
+	assert.Nil(t, tree.root)
+	assert.Equal(t, len(words), snapshot.Size())
+	assert.Equal(t, len(words)/2, second.Size())
+
+	for i, w := range words {
+		assert.Equal(t, w, snapshot.Search(w))
+		value, ok := second.Get(w)
+		if i%2 == 0 {
+			assert.False(t, ok)
+		} else {
+			assert.Equal(t, i, value)
+		}
+	}
+
+	count := 0
+	snapshot.Each(func(node Node) {
+		if node.NodeType() == LeafNode {
+			count++
+		}
+	})
+	assert.Equal(t, len(words), count)
+}
+
+func TestInsertExistingKeyEndingAtInnerNode(t *testing.T) {
+	tree := newArt[Value]()
+	tree.Insert(Key("a"), 1)
+	tree.Insert(Key("ab"), 2)
+
+	old, replaced := tree.Swap(Key("a"), 3)
+	assert.True(t, replaced)
+	assert.Equal(t, 1, old)
+	assert.Equal(t, 3, tree.Search(Key("a")))
+	assert.Equal(t, 2, tree.Size())
+}
+
+func TestWalkStop(t *testing.T) {
+	tree := newArt[Value]()
+	words := testdata.LoadTestFile("testdata/data/words.txt")
+	for _, w := range words {
+		tree.Insert(w, w)
+	}
+
+	var visited int
+	var found Key
+	tree.Walk(func(node Node) WalkAction {
+		visited++
+		if node.NodeType() == LeafNode && bytes.HasPrefix(node.Key(), Key("b")) {
+			found = node.Key()
+			return WalkStop
+		}
+		return WalkContinue
+	})
+
+	assert.Equal(t, Key("b"), found)
+	assert.Less(t, visited, len(words)/2)
+}
+
+func TestWalkSkipChildren(t *testing.T) {
+	tree := newArt[Value]()
+	for _, key := range []string{"apple", "apricot", "banana", "blueberry", "cherry"} {
+		tree.Insert(Key(key), key)
+	}
+
+	var keys []string
+	tree.Walk(func(node Node) WalkAction {
+		if node.NodeType() == LeafNode {
+			keys = append(keys, string(node.Key()))
+			return WalkContinue
+		}
+		if node != Node(tree.root) {
+			return WalkSkipChildren
+		}
+		return WalkContinue
+	})
+
+	assert.Equal(t, []string{"cherry"}, keys)
+}
+
+func TestWalkVisitsLikeEach(t *testing.T) {
+	tree := newArt[Value]()
+	words := testdata.LoadTestFile("testdata/data/words.txt")
+	for _, w := range words {
+		tree.Insert(w, w)
+	}
+
+	var each, walk []Node
+	tree.Each(func(node Node) {
+		each = append(each, node)
+	})
+	tree.Walk(func(node Node) WalkAction {
+		walk = append(walk, node)
+		return WalkContinue
+	})
+	assert.Equal(t, len(each), len(walk))
+	assert.True(t, each[len(each)-1] == walk[len(walk)-1])
+}
+
+func TestWalkWithOptions(t *testing.T) {
+	tree := newArt[Value]()
+	for _, key := range []string{"ab", "ac", "b"} {
+		tree.Insert(Key(key), key)
+	}
+	// root Node4 {'a': Node4 {'b': "ab", 'c': "ac"}, 'b': "b"}
+	inner := *tree.root.findChild('a')
+
+	testCases := []struct {
+		opts     WalkOptions
+		expected []Node
+	}{
+		{WalkOptions{}, []Node{tree.root, inner, tree.root.minimum(), *inner.findChild('c'), tree.root.maximum()}},
+		{WalkOptions{Nodes: WalkLeaves}, []Node{tree.root.minimum(), *inner.findChild('c'), tree.root.maximum()}},
+		{WalkOptions{Nodes: WalkInnerNodes}, []Node{tree.root, inner}},
+		{WalkOptions{PostOrder: true}, []Node{tree.root.minimum(), *inner.findChild('c'), inner, tree.root.maximum(), tree.root}},
+		{WalkOptions{Descending: true}, []Node{tree.root, tree.root.maximum(), inner, *inner.findChild('c'), tree.root.minimum()}},
+		{WalkOptions{Nodes: WalkInnerNodes, PostOrder: true, Descending: true}, []Node{inner, tree.root}},
+	}
+
+	for _, tc := range testCases {
+		var traversal []Node
+		tree.EachWith(tc.opts, func(node Node) {
+			traversal = append(traversal, node)
+		})
+		assert.Equal(t, len(tc.expected), len(traversal), "%+v", tc.opts)
+		for i := range tc.expected {
+			assert.True(t, tc.expected[i] == traversal[i], "%+v: node %d", tc.opts, i)
+		}
+	}
+}
+
+func TestWalkWithLatestLeaves(t *testing.T) {
+	tree := newArt[Value]()
+	words := testdata.LoadTestFile("testdata/data/words.txt")
+	for _, w := range words {
+		tree.Insert(w, w)
+	}
+
+	var latest []Key
+	tree.WalkWith(WalkOptions{Nodes: WalkLeaves, Descending: true}, func(node Node) WalkAction {
+		latest = append(latest, node.Key())
+		if len(latest) == 3 {
+			return WalkStop
+		}
+		return WalkContinue
+	})
+
+	sort.Slice(words, func(i, j int) bool {
+		return bytes.Compare(words[i], words[j]) > 0
+	})
+	assert.Equal(t, words[:3], latest)
+}
+
+func TestKeysWithZeroBytesAndPrefixes(t *testing.T) {
+	keys := []Key{
+		Key("ab"),
+		Key("ab\x00"),
+		Key("ab\x00\x00"),
+		Key("ab\x00c"),
+		Key("abc"),
+		Key("a"),
+		Key("\x00"),
+		Key("\x00\x00"),
+	}
+
+	tree := newArt[Value]()
+	for i, key := range keys {
+		tree.Insert(key, i)
+		assert.NoError(t, tree.Validate())
+	}
+	assert.Equal(t, len(keys), tree.Size())
+
+	for i, key := range keys {
+		assert.Equal(t, i, tree.Search(key), "%q", key)
+	}
+	_, ok := tree.Get(Key("ab\x00\x00\x00"))
+	assert.False(t, ok)
+	_, ok = tree.Get(Key("abc\x00"))
+	assert.False(t, ok)
+
+	var ordered []Key
+	for k := range tree.All() {
+		ordered = append(ordered, k)
+	}
+	sorted := append([]Key(nil), keys...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i], sorted[j]) < 0
+	})
+	assert.Equal(t, sorted, ordered)
+
+	key, _, _ := tree.LongestPrefix(Key("ab\x00\x00\x00"))
+	assert.Equal(t, Key("ab\x00\x00"), key)
+	key, _, _ = tree.LongestPrefix(Key("ab\x01"))
+	assert.Equal(t, Key("ab"), key)
+
+	for i, key := range keys {
+		old, ok := tree.Remove(key)
+		assert.True(t, ok, "%q", key)
+		assert.Equal(t, i, old)
+		assert.NoError(t, tree.Validate())
+		for _, other := range keys[i+1:] {
+			_, ok := tree.Get(other)
+			assert.True(t, ok, "%q after removing %q", other, key)
+		}
+	}
+	assert.Nil(t, tree.root)
+}
+
+func TestRandomBinaryKeys(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	randomKey := func() Key {
+		key := make(Key, 1+rnd.Intn(6))
+		for i := range key {
+			key[i] = []byte{0, 1, 'a'}[rnd.Intn(3)]
+		}
+		return key
+	}
+
+	tree := newArt[int]()
+	expected := make(map[string]int)
+	for i := 0; i < 20000; i++ {
+		key := randomKey()
+		if rnd.Intn(3) == 0 {
+			_, ok := tree.Remove(key)
+			_, exists := expected[string(key)]
+			assert.Equal(t, exists, ok, "%q", key)
+			delete(expected, string(key))
+		} else {
+			tree.Insert(key, i)
+			expected[string(key)] = i
+		}
+	}
+	assert.NoError(t, tree.Validate())
+	assert.Equal(t, len(expected), tree.Size())
+
+	var keys []string
+	for k, v := range tree.All() {
+		assert.Equal(t, expected[string(k)], v)
+		keys = append(keys, string(k))
+	}
+	assert.True(t, sort.StringsAreSorted(keys))
+	assert.Equal(t, len(expected), len(keys))
+
+	c := tree.Cursor()
+	for i := len(keys) - 1; i >= 0; i-- {
+		if i == len(keys)-1 {
+			assert.True(t, c.Last())
+		} else {
+			assert.True(t, c.Prev())
+		}
+		assert.Equal(t, keys[i], string(c.Key()))
+	}
+	assert.False(t, c.Prev())
+}
+
+func TestEmptyKey(t *testing.T) {
+	tree := newArt[Value]()
+
+	_, ok := tree.Get(Key(""))
+	assert.False(t, ok)
+	assert.False(t, tree.Delete(Key("")))
+
+	tree.Insert(Key(""), "root")
+	assert.Equal(t, 1, tree.Size())
+	assert.Equal(t, "root", tree.Search(nil))
+	assert.Equal(t, LeafNode, tree.root.nodeType)
+
+	for _, key := range []string{"a", "ab", "b"} {
+		tree.Insert(Key(key), key)
+	}
+	tree.Insert(Key(""), "settings")
+	assert.Equal(t, 4, tree.Size())
+	assert.Equal(t, "settings", tree.Search(Key("")))
+	assert.NoError(t, tree.Validate())
+
+	var keys []string
+	tree.Each(func(node Node) {
+		if node.NodeType() == LeafNode {
+			keys = append(keys, string(node.Key()))
+		}
+	})
+	assert.Equal(t, []string{"", "a", "ab", "b"}, keys)
+
+	key, value, ok := tree.LongestPrefix(Key("c"))
+	assert.True(t, ok)
+	assert.Equal(t, Key(""), key)
+	assert.Equal(t, "settings", value)
+
+	old, ok := tree.Remove(Key(""))
+	assert.True(t, ok)
+	assert.Equal(t, "settings", old)
+	assert.Equal(t, 3, tree.Size())
+	assert.NoError(t, tree.Validate())
+	_, ok = tree.Get(Key(""))
+	assert.False(t, ok)
+	assert.Equal(t, "ab", tree.Search(Key("ab")))
+}
+
+func TestEmptyKeyBelowCompressedPath(t *testing.T) {
+	tree := newArt[Value]()
+	tree.Insert(Key("prefix/a"), 1)
+	tree.Insert(Key("prefix/b"), 2)
+	tree.Insert(Key(""), 0)
+	assert.NoError(t, tree.Validate())
+
+	c := tree.Cursor()
+	assert.True(t, c.First())
+	assert.Equal(t, Key(""), c.Key())
+	assert.True(t, c.Seek(Key("")))
+	assert.Equal(t, Key(""), c.Key())
+
+	assert.True(t, tree.Delete(Key("")))
+	assert.NoError(t, tree.Validate())
+	assert.Equal(t, 2, tree.Size())
+	assert.Equal(t, 1, tree.Search(Key("prefix/a")))
+}
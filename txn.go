@@ -0,0 +1,94 @@
+package art
+
+// txn - adaptive radix tree transaction type.
+type txn[V any] struct {
+	*tree[V]
+	parent *tree[V]
+	base   *artNode[V]
+	closed bool
+}
+
+// Txn starts a transaction on the tree. The tree must not be modified
+// while the transaction is open, or Commit will fail with ErrTxnConflict.
+// Like Snapshot, Txn counts as a write to the tree and must be serialized with the other writes,
+// and the writes to both the tree and the transaction copy the paths they change.
+func (t *tree[V]) Txn() TypedTxn[V] {
+	return &txn[V]{tree: t.fork(), parent: t, base: t.root}
+}
+
+// Commit publishes the writes of the transaction to the tree it was started on.
+func (x *txn[V]) Commit() error {
+	if x.closed {
+		return ErrTxnClosed
+	}
+	x.closed = true
+	if x.parent.root != x.base {
+		return ErrTxnConflict
+	}
+
+	x.parent.root = x.root
+	x.parent.size = x.size
+	x.parent.gen = x.gen
+	// The published nodes now belong to the tree.
+	x.gen = newGen()
+	return nil
+}
+
+// Rollback discards the writes of the transaction.
+func (x *txn[V]) Rollback() {
+	x.closed = true
+	x.root = nil
+	x.size = 0
+}
+
+// checkOpen panics with ErrTxnClosed once the transaction has been committed or rolled back,
+// so that writes to a closed transaction are never silently dropped.
+func (x *txn[V]) checkOpen() {
+	if x.closed {
+		panic(ErrTxnClosed)
+	}
+}
+
+// Insert inserts the passed in value that is indexed by the passed in key into the transaction.
+func (x *txn[V]) Insert(key Key, value V) {
+	x.checkOpen()
+	x.tree.Insert(key, value)
+}
+
+// Swap inserts the passed in value that is indexed by the passed in key into the transaction,
+// and returns the value it replaced, if any.
+func (x *txn[V]) Swap(key Key, value V) (V, bool) {
+	x.checkOpen()
+	return x.tree.Swap(key, value)
+}
+
+// InsertIfAbsent inserts the passed in value that is indexed by the passed in key into the transaction
+// if the key is not present yet. Otherwise it returns the existing value.
+func (x *txn[V]) InsertIfAbsent(key Key, value V) (V, bool) {
+	x.checkOpen()
+	return x.tree.InsertIfAbsent(key, value)
+}
+
+// Upsert stores the value returned by the given function for the passed in key in the transaction.
+func (x *txn[V]) Upsert(key Key, fn TypedUpsertFunc[V]) {
+	x.checkOpen()
+	x.tree.Upsert(key, fn)
+}
+
+// Delete deletes the child of the passed in key from the transaction.
+func (x *txn[V]) Delete(key Key) bool {
+	x.checkOpen()
+	return x.tree.Delete(key)
+}
+
+// Remove deletes the child of the passed in key from the transaction, and returns its value.
+func (x *txn[V]) Remove(key Key) (V, bool) {
+	x.checkOpen()
+	return x.tree.Remove(key)
+}
+
+// Txn starts a nested transaction, which commits into this one.
+func (x *txn[V]) Txn() TypedTxn[V] {
+	x.checkOpen()
+	return x.tree.Txn()
+}
@@ -0,0 +1,116 @@
+package art
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"art/testdata"
+)
+
+func TestTxnCommit(t *testing.T) {
+	tree := newArt[Value]()
+	tree.Insert(Key("a"), 1)
+	tree.Insert(Key("b"), 2)
+
+	x := tree.Txn()
+	x.Insert(Key("c"), 3)
+	x.Delete(Key("a"))
+	x.Insert(Key("b"), 20)
+
+	assert.Equal(t, 20, x.Search(Key("b")))
+	assert.Equal(t, 2, tree.Search(Key("b")))
+	assert.Equal(t, 1, tree.Search(Key("a")))
+	_, ok := tree.Get(Key("c"))
+	assert.False(t, ok)
+
+	assert.NoError(t, x.Commit())
+	assert.Equal(t, ErrTxnClosed, x.Commit())
+
+	assert.Equal(t, 2, tree.Size())
+	_, ok = tree.Get(Key("a"))
+	assert.False(t, ok)
+	assert.Equal(t, 20, tree.Search(Key("b")))
+	assert.Equal(t, 3, tree.Search(Key("c")))
+
+	// Writes to a committed transaction fail instead of being dropped.
+	assert.PanicsWithValue(t, ErrTxnClosed, func() { x.Insert(Key("d"), 4) })
+	assert.PanicsWithValue(t, ErrTxnClosed, func() { x.Delete(Key("b")) })
+	assert.Equal(t, 20, x.Search(Key("b")))
+	_, ok = tree.Get(Key("d"))
+	assert.False(t, ok)
+}
+
+func TestTxnRollback(t *testing.T) {
+	tree := newArt[Value]()
+
+	words := testdata.LoadTestFile("testdata/data/words.txt")
+	for _, w := range words[:10000] {
+		tree.Insert(w, w)
+	}
+
+	x := tree.Txn()
+	for _, w := range words[:10000] {
+		x.Delete(w)
+	}
+	for _, w := range words[10000:20000] {
+		x.Insert(w, w)
+	}
+	assert.Equal(t, 10000, x.Size())
+	x.Rollback()
+
+	assert.Equal(t, ErrTxnClosed, x.Commit())
+	assert.Zero(t, x.Size())
+	assert.PanicsWithValue(t, ErrTxnClosed, func() { x.Upsert(words[0], func(Value, bool) Value { return nil }) })
+	assert.PanicsWithValue(t, ErrTxnClosed, func() { x.Txn() })
+	assert.Equal(t, 10000, tree.Size())
+	for _, w := range words[:10000] {
+		assert.Equal(t, w, tree.Search(w))
+	}
+	_, ok := tree.Get(words[10000])
+	assert.False(t, ok)
+}
+
+func TestTxnConflict(t *testing.T) {
+	tree := newArt[Value]()
+	tree.Insert(Key("a"), 1)
+
+	x := tree.Txn()
+	x.Insert(Key("b"), 2)
+	tree.Insert(Key("c"), 3)
+
+	assert.Equal(t, ErrTxnConflict, x.Commit())
+	_, ok := tree.Get(Key("b"))
+	assert.False(t, ok)
+	assert.Equal(t, 3, tree.Search(Key("c")))
+
+	first, second := tree.Txn(), tree.Txn()
+	first.Insert(Key("d"), 4)
+	second.Insert(Key("e"), 5)
+	assert.NoError(t, first.Commit())
+	assert.Equal(t, ErrTxnConflict, second.Commit())
+	assert.Equal(t, 3, tree.Size())
+}
+
+func TestTxnCommitAfterNoopWrites(t *testing.T) {
+	tree := newArt[Value]()
+	tree.Insert(Key("a"), 1)
+	tree.Insert(Key("ab"), 2)
+	tree.Insert(Key("b"), 3)
+
+	x := tree.Txn()
+	x.Insert(Key("c"), 4)
+
+	// Writes that leave the tree unchanged do not copy any node.
+	_, inserted := tree.InsertIfAbsent(Key("a"), 10)
+	assert.False(t, inserted)
+	_, inserted = tree.InsertIfAbsent(Key("ab"), 20)
+	assert.False(t, inserted)
+	assert.False(t, tree.Delete(Key("x")))
+	assert.False(t, tree.Delete(Key("abc")))
+
+	assert.NoError(t, x.Commit())
+	assert.Equal(t, 4, tree.Size())
+	assert.Equal(t, 1, tree.Search(Key("a")))
+	assert.Equal(t, 4, tree.Search(Key("c")))
+}
@@ -0,0 +1,149 @@
+package art
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidTree is returned by Validate when the tree violates one of its invariants.
+var ErrInvalidTree = errors.New("art: invalid tree")
+
+// Validate walks the whole tree and checks its structural invariants,
+// returning an error describing the first broken node it finds.
+func (t *tree[V]) Validate() error {
+	leaves, err := t.validateHelper(t.root, nil)
+	if err != nil {
+		return err
+	}
+	if leaves != int(t.size) {
+		return fmt.Errorf("%w: size is %d, found %d leaves", ErrInvalidTree, t.size, leaves)
+	}
+	return nil
+}
+
+// validateHelper is a helper function of Validate.
+// The passed in path holds the key bytes every leaf below the current artNode must start with,
+// it returns the number of leaves below the current artNode.
+func (t *tree[V]) validateHelper(current *artNode[V], path []byte) (int, error) {
+	if current == nil {
+		return 0, nil
+	}
+
+	at := path
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: node of type %v at %q: %s", ErrInvalidTree, current.nodeType, at, fmt.Sprintf(format, args...))
+	}
+
+	if current.isLeaf() {
+		if key := current.leafNode().key; !bytes.HasPrefix(key, path) {
+			return 0, invalid("leaf key %q does not match its path", key)
+		}
+		return 1, nil
+	}
+
+	if err := current.validateChildren(); err != nil {
+		return 0, invalid("%v", err)
+	}
+
+	node := current.node()
+	depth := len(path)
+	path = append(path, node.prefix[:min(node.prefixLen, maxPrefixLen)]...)
+	if node.prefixLen > maxPrefixLen {
+		// Only the first maxPrefixLen bytes are stored, the others are those of any leaf below.
+		key := current.minimum().leafNode().key
+		if len(key) < depth+node.prefixLen {
+			return 0, invalid("prefix of length %d is longer than leaf key %q", node.prefixLen, key)
+		}
+		path = append(path, key[depth+maxPrefixLen:depth+node.prefixLen]...)
+	}
+
+	leaves := 0
+	if terminal := node.terminal; terminal != nil {
+		if !terminal.isLeaf() || !bytes.Equal(terminal.leafNode().key, path) {
+			return 0, invalid("terminal node does not hold the key %q", path)
+		}
+		leaves++
+	}
+	for pos, child := current.nextChild(0); child != nil; pos, child = current.nextChild(pos + 1) {
+		count, err := t.validateHelper(child, append(path, current.keyAt(pos)))
+		if err != nil {
+			return 0, err
+		}
+		leaves += count
+	}
+	if leaves != node.count {
+		return 0, invalid("count is %d, found %d leaves", node.count, leaves)
+	}
+	return leaves, nil
+}
+
+// validateChildren checks the number and the layout of the children of the current inner artNode.
+func (n *artNode[V]) validateChildren() error {
+	size := n.node().size
+	if n.isUnderfull() || size > n.maxSize() {
+		return fmt.Errorf("size %d out of range [%d, %d]", size, n.minSize(), n.maxSize())
+	}
+
+	switch n.nodeType {
+	case Node4:
+		n4 := n.node4()
+		return validateSorted(n4.keys[:], n4.children[:], size)
+	case Node16:
+		n16 := n.node16()
+		return validateSorted(n16.keys[:], n16.children[:], size)
+	case Node48:
+		n48 := n.node48()
+		var used [node48Max + 1]bool
+		count := 0
+		for key, idx := range n48.keys {
+			if idx == 0 {
+				continue
+			}
+			if int(idx) > node48Max || n48.children[idx] == nil {
+				return fmt.Errorf("key %#x refers to empty child slot %d", key, idx)
+			}
+			if used[idx] {
+				return fmt.Errorf("child slot %d is referred to more than once", idx)
+			}
+			used[idx] = true
+			count++
+		}
+		for idx, child := range n48.children {
+			if child != nil && !used[idx] {
+				return fmt.Errorf("child slot %d is not referred to by any key", idx)
+			}
+		}
+		if count != size {
+			return fmt.Errorf("size is %d, found %d children", size, count)
+		}
+	case Node256:
+		count := 0
+		for _, child := range n.node256().children {
+			if child != nil {
+				count++
+			}
+		}
+		if count != size {
+			return fmt.Errorf("size is %d, found %d children", size, count)
+		}
+	}
+	return nil
+}
+
+// validateSorted checks that the first size keys of a Node4 or Node16 are strictly increasing,
+// and that exactly the first size children are set.
+func validateSorted[V any](keys []byte, children []*artNode[V], size int) error {
+	for i := range children {
+		if children[i] == nil && i < size {
+			return fmt.Errorf("size is %d, child slot %d is empty", size, i)
+		}
+		if children[i] != nil && i >= size {
+			return fmt.Errorf("size is %d, child slot %d is set", size, i)
+		}
+		if i > 0 && i < size && keys[i-1] >= keys[i] {
+			return fmt.Errorf("keys %#x and %#x are not sorted", keys[i-1], keys[i])
+		}
+	}
+	return nil
+}
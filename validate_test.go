@@ -0,0 +1,90 @@
+package art
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"art/testdata"
+)
+
+func TestValidateEmptyTree(t *testing.T) {
+	assert.NoError(t, newArt[Value]().Validate())
+}
+
+func TestValidateInsertAndDeleteWords(t *testing.T) {
+	tree := newArt[Value]()
+
+	words := testdata.LoadTestFile("testdata/data/words.txt")
+	for _, w := range words {
+		tree.Insert(w, w)
+	}
+	assert.NoError(t, tree.Validate())
+
+	for i, w := range words {
+		if i%3 != 0 {
+			tree.Delete(w)
+		}
+	}
+	assert.NoError(t, tree.Validate())
+
+	for _, w := range words {
+		tree.Delete(w)
+	}
+	assert.NoError(t, tree.Validate())
+	assert.Nil(t, tree.root)
+}
+
+func TestValidateLongPrefixes(t *testing.T) {
+	tree := newArt[Value]()
+	tree.Insert(Key("averyveryverylongprefix/a/1"), 1)
+	tree.Insert(Key("averyveryverylongprefix/a/2"), 2)
+	tree.Insert(Key("averyveryverylongprefix/b"), 3)
+	assert.NoError(t, tree.Validate())
+
+	tree.Delete(Key("averyveryverylongprefix/b"))
+	assert.NoError(t, tree.Validate())
+}
+
+func TestValidateDetectsCorruption(t *testing.T) {
+	build := func() *tree[Value] {
+		tree := newArt[Value]()
+		for _, key := range []string{"abc/1", "abc/2", "abc/3", "abd"} {
+			tree.Insert(Key(key), key)
+		}
+		return tree
+	}
+
+	tree := build()
+	assert.NoError(t, tree.Validate())
+
+	tree = build()
+	tree.size++
+	assert.ErrorIs(t, tree.Validate(), ErrInvalidTree)
+
+	tree = build()
+	tree.root.node().prefix[0] = 'x'
+	assert.ErrorIs(t, tree.Validate(), ErrInvalidTree)
+
+	tree = build()
+	n4 := tree.root.node4()
+	n4.keys[0], n4.keys[1] = n4.keys[1], n4.keys[0]
+	assert.ErrorIs(t, tree.Validate(), ErrInvalidTree)
+
+	tree = build()
+	tree.root.node4().size = 1
+	assert.ErrorIs(t, tree.Validate(), ErrInvalidTree)
+}
+
+func TestValidateDetectsNode48Corruption(t *testing.T) {
+	tree := newArt[Value]()
+	for i := 0; i < node16Max+1; i++ {
+		tree.Insert(Key{byte(i), 'x'}, i)
+	}
+	assert.Equal(t, Node48, tree.root.nodeType)
+	assert.NoError(t, tree.Validate())
+
+	n48 := tree.root.node48()
+	n48.keys[1] = n48.keys[0]
+	assert.ErrorIs(t, tree.Validate(), ErrInvalidTree)
+}